@@ -3,33 +3,63 @@ package main
 import (
 	"context"
 	"fmt"
+	"github.com/Spazzy757/paul/pkg/config"
+	"github.com/Spazzy757/paul/pkg/github"
 	"github.com/Spazzy757/paul/pkg/helpers"
+	"github.com/Spazzy757/paul/pkg/queue"
 	"github.com/Spazzy757/paul/pkg/router"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 )
 
 func main() {
 	// Termination Handeling
 	termChan := make(chan os.Signal, 1)
 	signal.Notify(termChan, syscall.SIGINT, syscall.SIGTERM)
+	// Reload the server config without restarting
+	reloadChan := make(chan os.Signal, 1)
+	signal.Notify(reloadChan, syscall.SIGHUP)
+	// Load and validate the server config
+	configPath := helpers.GetEnv("PAUL_CONFIG", "")
+	cfg, err := config.LoadServerConfig(configPath)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	config.SetServerConfig(cfg)
+	// Refuse to run without a way to check webhooks are from GitHub
+	checkWebhookSecrets()
+	// Webhooks that keep failing are kept on disk so they can be replayed
+	deadLetters, err := queue.OpenFileStore(cfg.Queue.DeadLetterPath)
+	if err != nil {
+		log.Fatalf("Unable to open dead letters: %v", err)
+	}
+	defer deadLetters.Close()
+	// Start the workers that process webhooks
+	retrier := queue.NewRetrier(github.ProcessJob, queue.RetryPolicy{
+		Attempts:   cfg.Queue.RetryAttempts,
+		Backoff:    cfg.Queue.RetryBackoff,
+		MaxBackoff: cfg.Queue.RetryMaxBackoff,
+		Retryable:  github.Retryable,
+	}, deadLetters)
+	pool := queue.NewPool(cfg.Queue.Workers, cfg.Queue.Size, github.ForgetFailedDeliveries(retrier.Handle))
+	// Skip webhooks GitHub delivers more than once
+	github.UseDeliveryStore(github.NewMemoryDeliveryStore(
+		cfg.Queue.DeliveryCacheSize,
+		cfg.Queue.DeliveryTTL,
+	))
 	// Get the routes
-	router := router.GetRouter()
-	// Set server configuration
-	port := helpers.GetEnv("SERVER_PORT", "8000")
-	host := helpers.GetEnv("SERVER_HOST", "127.0.0.1")
+	router := router.GetRouter(pool, deadLetters)
 	// String formatting to join the host and port
-	addr := fmt.Sprintf("%v:%v", host, port)
+	addr := fmt.Sprintf("%v:%v", cfg.Server.Host, cfg.Server.Port)
 	// Setup Server
 	srv := &http.Server{
 		Handler:      router,
 		Addr:         addr,
-		WriteTimeout: 15 * time.Second,
-		ReadTimeout:  15 * time.Second,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		ReadTimeout:  cfg.Server.ReadTimeout,
 	}
 	// Run Server in Goroutine to handle Graceful Shutdowns
 	go func() {
@@ -37,15 +67,69 @@ func main() {
 			log.Fatalf("listen: %s\n", err)
 		}
 	}()
-	log.Printf("Starting Server at :%v", addr)
-	<-termChan
+	log.Printf("Starting Server at :%v with %v workers", addr, cfg.Queue.Workers)
+	for running := true; running; {
+		select {
+		case <-reloadChan:
+			reloadServerConfig(configPath)
+		case <-termChan:
+			running = false
+		}
+	}
 	// Any Code to Gracefully Shutdown should be done here
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 	defer func() {
 		cancel()
 	}()
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Fatalf("Server Shutdown Failed:%+v", err)
 	}
+	// Finish the webhooks that were already accepted, webhooks waiting
+	// to be retried get a last attempt and are dead-lettered if it fails
+	// instead of holding up the shutdown
+	retrier.Stop()
+	drainCtx, drainCancel := context.WithTimeout(context.Background(), cfg.Server.DrainTimeout)
+	defer drainCancel()
+	if err := pool.Shutdown(drainCtx); err != nil {
+		log.Printf("Webhooks still in flight were dropped:%+v", err)
+	}
 	log.Println("Shutting Down Gracefully")
 }
+
+/*
+reloadServerConfig loads the server config again, an invalid config is
+logged and the previous one is kept. The server and queue settings are
+kept until Paul is restarted
+*/
+func reloadServerConfig(configPath string) {
+	cfg, err := config.LoadServerConfig(configPath)
+	if err != nil {
+		log.Printf("Unable to reload server config, keeping the previous one: %v", err)
+		return
+	}
+	previous := config.CurrentServerConfig()
+	if changed := config.RestartRequired(previous, cfg); len(changed) > 0 {
+		log.Printf("Changes to the %v settings take effect when Paul is restarted", changed)
+		cfg.Server = previous.Server
+		cfg.Queue = previous.Queue
+	}
+	config.SetServerConfig(cfg)
+	github.ReloadAppConfig()
+	log.Println("Reloaded server config")
+}
+
+// checkWebhookSecrets stops Paul from starting without a webhook secret
+// unless insecure_dev is set for local testing
+func checkWebhookSecrets() {
+	secrets, err := config.WebhookSecrets()
+	if err != nil {
+		log.Fatalf("Unable to load webhook secrets: %v", err)
+	}
+	if len(secrets) > 0 {
+		return
+	}
+	if !config.InsecureDev() {
+		log.Fatalf("No webhook secret is configured, set SECRET_PATH or SECRET_KEY, or INSECURE_DEV=true for local testing")
+	}
+	log.Println("WARNING: INSECURE_DEV is set, unsigned webhooks are accepted")
+}
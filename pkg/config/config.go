@@ -3,44 +3,42 @@ package config
 import (
 	"fmt"
 	"io/ioutil"
-	"os"
 	"path"
-	"strings"
 )
 
 const (
 	secretKeyFile  = "paul-secret-key"
 	privateKeyFile = "paul-private-key"
+	// DefaultAPIURL is the API of github.com
+	DefaultAPIURL = "https://api.github.com/"
+	// DefaultUploadURL is the upload API of github.com
+	DefaultUploadURL = "https://uploads.github.com/"
 )
 
 // Config to run Derek
 type Config struct {
-	SecretKey     string
 	PrivateKey    string
 	ApplicationID string
+	// APIURL is the REST API used for everything, including minting
+	// installation tokens, for GitHub Enterprise Server this is
+	// https://HOSTNAME/api/v3/
+	APIURL string
+	// UploadURL is the upload API, for GitHub Enterprise Server this is
+	// https://HOSTNAME/api/uploads/
+	UploadURL string
 }
 
 // NewConfig populates configuration from known-locations and gives
-// an error if configuration is missing from disk or environmental variables
+// an error if configuration is missing from disk or the server config
 func NewConfig() (Config, error) {
 	config := Config{}
+	server := CurrentServerConfig()
 
-	keyPath, pathErr := getSecretPath()
+	keyPath, pathErr := getSecretPath(server)
 	if pathErr != nil {
 		return config, pathErr
 	}
 
-	secretKeyBytes, readErr := ioutil.ReadFile(path.Join(keyPath, secretKeyFile))
-
-	if readErr != nil {
-		msg := fmt.Errorf("unable to read GitHub symmetrical secret: %s, error: %s",
-			keyPath+secretKeyFile, readErr)
-		return config, msg
-	}
-
-	secretKeyBytes = getFirstLine(secretKeyBytes)
-	config.SecretKey = string(secretKeyBytes)
-
 	privateKeyPath := path.Join(keyPath, privateKeyFile)
 
 	keyBytes, err := ioutil.ReadFile(privateKeyPath)
@@ -50,17 +48,20 @@ func NewConfig() (Config, error) {
 
 	config.PrivateKey = string(keyBytes)
 
-	if val, ok := os.LookupEnv("APPLICATION_ID"); ok && len(val) > 0 {
-		config.ApplicationID = val
+	if len(server.GitHub.ApplicationID) > 0 {
+		config.ApplicationID = server.GitHub.ApplicationID
 	} else {
 		return config, fmt.Errorf("APPLICATION_ID must be given")
 	}
 
+	config.APIURL = server.GitHub.APIURL
+	config.UploadURL = server.GitHub.UploadURL
+
 	return config, nil
 }
 
-func getSecretPath() (string, error) {
-	secretPath := os.Getenv("SECRET_PATH")
+func getSecretPath(server ServerConfig) (string, error) {
+	secretPath := server.GitHub.SecretPath
 
 	if len(secretPath) == 0 {
 		return "", fmt.Errorf("SECRET_PATH env-var not set")
@@ -68,11 +69,3 @@ func getSecretPath() (string, error) {
 
 	return secretPath, nil
 }
-
-func getFirstLine(secret []byte) []byte {
-	stringSecret := string(secret)
-	if newLine := strings.Index(stringSecret, "\n"); newLine != -1 {
-		secret = secret[:newLine]
-	}
-	return secret
-}
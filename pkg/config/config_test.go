@@ -26,15 +26,12 @@ func TestNewConfigNoSecretPath(t *testing.T) {
 
 func TestNewConfigValidSecretPathWithApplicationID(t *testing.T) {
 	privateWant := "private"
-	secretWant := "secret"
 	appIDWant := "321"
 	tmpDir := os.TempDir()
 
 	ioutil.WriteFile(path.Join(tmpDir, "paul-private-key"), []byte(privateWant), 0600)
-	ioutil.WriteFile(path.Join(tmpDir, "paul-secret-key"), []byte(secretWant), 0600)
 
 	defer os.RemoveAll(path.Join(tmpDir, "paul-private-key"))
-	defer os.RemoveAll(path.Join(tmpDir, "paul-secret-key"))
 
 	os.Setenv("SECRET_PATH", tmpDir)
 	os.Setenv("APPLICATION_ID", appIDWant)
@@ -47,11 +44,6 @@ func TestNewConfigValidSecretPathWithApplicationID(t *testing.T) {
 		return
 	}
 
-	if cfg.SecretKey != secretWant {
-		t.Errorf("want %q, got %q", secretWant, cfg.SecretKey)
-		t.Fail()
-	}
-
 	if cfg.PrivateKey != privateWant {
 		t.Errorf("want %q, got %q", privateWant, cfg.PrivateKey)
 		t.Fail()
@@ -62,41 +54,3 @@ func TestNewConfigValidSecretPathWithApplicationID(t *testing.T) {
 		t.Fail()
 	}
 }
-
-func TestGetFirstLine(t *testing.T) {
-	var exampleSecrets = []struct {
-		secret       string
-		expectedByte string
-	}{
-		{
-			secret:       "New-line \n",
-			expectedByte: "New-line ",
-		},
-		{
-			secret: `Newline and text 
-			`,
-			expectedByte: "Newline and text ",
-		},
-		{
-			secret:       `Example secret2 `,
-			expectedByte: `Example secret2 `,
-		},
-		{
-			secret:       "\n",
-			expectedByte: "",
-		},
-		{
-			secret:       "",
-			expectedByte: "",
-		},
-	}
-	for _, test := range exampleSecrets {
-
-		t.Run(string(test.secret), func(t *testing.T) {
-			stringNoLines := getFirstLine([]byte(test.secret))
-			if test.expectedByte != string(stringNoLines) {
-				t.Errorf("String after removal - wanted: \"%s\", got \"%s\"", test.expectedByte, test.secret)
-			}
-		})
-	}
-}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// secretFile caches the webhook secrets read from disk until the file changes
+var secretFile struct {
+	sync.Mutex
+	path    string
+	modTime time.Time
+	size    int64
+	secrets []string
+}
+
+/*
+WebhookSecrets returns the secrets webhooks can be signed with, the current
+secret first followed by previous secrets that are still accepted while
+rotating. They are read from the paul-secret-key file in the secret path,
+one secret per line, and read again whenever the file changes on disk.
+Without a secret path the comma separated SECRET_KEY env-var is used
+*/
+func WebhookSecrets() ([]string, error) {
+	keyPath := CurrentServerConfig().GitHub.SecretPath
+	if len(keyPath) == 0 {
+		return splitSecrets(os.Getenv("SECRET_KEY"), ","), nil
+	}
+	secretPath := path.Join(keyPath, secretKeyFile)
+
+	secretFile.Lock()
+	defer secretFile.Unlock()
+	info, statErr := os.Stat(secretPath)
+	if statErr != nil {
+		return cachedSecrets(secretPath, statErr)
+	}
+	if secretFile.path == secretPath &&
+		info.ModTime().Equal(secretFile.modTime) &&
+		info.Size() == secretFile.size {
+		return secretFile.secrets, nil
+	}
+	secretBytes, readErr := ioutil.ReadFile(secretPath)
+	if readErr != nil {
+		return cachedSecrets(secretPath, readErr)
+	}
+	secrets := splitSecrets(string(secretBytes), "\n")
+	if secretFile.path == secretPath {
+		log.Printf("Reloaded %d webhook secrets from %s", len(secrets), secretPath)
+	}
+	secretFile.path = secretPath
+	secretFile.modTime = info.ModTime()
+	secretFile.size = info.Size()
+	secretFile.secrets = secrets
+	return secrets, nil
+}
+
+// cachedSecrets keeps the last secrets that were read when the file can
+// not be read, for example while it is being replaced
+func cachedSecrets(secretPath string, err error) ([]string, error) {
+	if secretFile.path == secretPath {
+		log.Printf("Unable to reload webhook secrets, using the previous ones: %s", err)
+		return secretFile.secrets, nil
+	}
+	return nil, fmt.Errorf("unable to read webhook secrets: %s, error: %s", secretPath, err)
+}
+
+// splitSecrets splits a list of secrets dropping empty entries
+func splitSecrets(value, sep string) []string {
+	var secrets []string
+	for _, secret := range strings.Split(value, sep) {
+		if secret = strings.TrimSpace(secret); len(secret) > 0 {
+			secrets = append(secrets, secret)
+		}
+	}
+	return secrets
+}
+
+/*
+InsecureDev reports whether insecure_dev is set in the server config, it
+lets Paul run without a webhook secret for local testing. Anyone who can
+reach Paul can then forge webhooks so it must never be set in production
+*/
+func InsecureDev() bool {
+	return CurrentServerConfig().InsecureDev
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWebhookSecrets(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "paul-secrets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	secretPath := path.Join(tmpDir, "paul-secret-key")
+	os.Setenv("SECRET_PATH", tmpDir)
+	defer os.Unsetenv("SECRET_PATH")
+
+	t.Run("Test Every Line Is A Secret", func(t *testing.T) {
+		ioutil.WriteFile(secretPath, []byte("current\nprevious\n"), 0600)
+		secrets, err := WebhookSecrets()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []string{"current", "previous"}
+		if !reflect.DeepEqual(secrets, want) {
+			t.Errorf("want %q, got %q", want, secrets)
+		}
+	})
+
+	t.Run("Test Secrets Are Reloaded When The File Changes", func(t *testing.T) {
+		ioutil.WriteFile(secretPath, []byte("next\ncurrent\n"), 0600)
+		later := time.Now().Add(time.Minute)
+		os.Chtimes(secretPath, later, later)
+		secrets, err := WebhookSecrets()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []string{"next", "current"}
+		if !reflect.DeepEqual(secrets, want) {
+			t.Errorf("want %q, got %q", want, secrets)
+		}
+	})
+
+	t.Run("Test Previous Secrets Are Kept While The File Is Missing", func(t *testing.T) {
+		os.Remove(secretPath)
+		secrets, err := WebhookSecrets()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []string{"next", "current"}
+		if !reflect.DeepEqual(secrets, want) {
+			t.Errorf("want %q, got %q", want, secrets)
+		}
+	})
+
+	t.Run("Test SECRET_KEY Is Used Without SECRET_PATH", func(t *testing.T) {
+		os.Unsetenv("SECRET_PATH")
+		os.Setenv("SECRET_KEY", "current, previous")
+		defer os.Unsetenv("SECRET_KEY")
+		secrets, err := WebhookSecrets()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []string{"current", "previous"}
+		if !reflect.DeepEqual(secrets, want) {
+			t.Errorf("want %q, got %q", want, secrets)
+		}
+	})
+}
@@ -0,0 +1,262 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ServerConfig configures the Paul server, it is read from the YAML file
+// in PAUL_CONFIG and env-vars override the values in the file
+type ServerConfig struct {
+	Server      ServerSettings `yaml:"server"`
+	GitHub      GitHubSettings `yaml:"github"`
+	Queue       QueueSettings  `yaml:"queue"`
+	InsecureDev bool           `yaml:"insecure_dev"`
+}
+
+// ServerSettings configures the HTTP server
+type ServerSettings struct {
+	Host            string        `yaml:"host"`
+	Port            int           `yaml:"port"`
+	ReadTimeout     time.Duration `yaml:"read_timeout"`
+	WriteTimeout    time.Duration `yaml:"write_timeout"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	DrainTimeout    time.Duration `yaml:"drain_timeout"`
+}
+
+// GitHubSettings configures how Paul talks to GitHub
+type GitHubSettings struct {
+	APIURL        string `yaml:"api_url"`
+	UploadURL     string `yaml:"upload_url"`
+	ApplicationID string `yaml:"application_id"`
+	SecretPath    string `yaml:"secret_path"`
+	// ConfigCacheTTL is how long a PAUL.yaml is used before checking
+	// whether it changed
+	ConfigCacheTTL time.Duration `yaml:"config_cache_ttl"`
+}
+
+// QueueSettings configures how webhooks are processed
+type QueueSettings struct {
+	Workers           int           `yaml:"workers"`
+	Size              int           `yaml:"size"`
+	RetryAttempts     int           `yaml:"retry_attempts"`
+	RetryBackoff      time.Duration `yaml:"retry_backoff"`
+	RetryMaxBackoff   time.Duration `yaml:"retry_max_backoff"`
+	DeadLetterPath    string        `yaml:"dead_letter_path"`
+	DeliveryCacheSize int           `yaml:"delivery_cache_size"`
+	DeliveryTTL       time.Duration `yaml:"delivery_ttl"`
+}
+
+// DefaultServerConfig returns the config used when nothing is configured
+func DefaultServerConfig() ServerConfig {
+	return ServerConfig{
+		Server: ServerSettings{
+			Host:            "127.0.0.1",
+			Port:            8000,
+			ReadTimeout:     15 * time.Second,
+			WriteTimeout:    15 * time.Second,
+			ShutdownTimeout: 5 * time.Second,
+			DrainTimeout:    20 * time.Second,
+		},
+		GitHub: GitHubSettings{
+			APIURL:         DefaultAPIURL,
+			UploadURL:      DefaultUploadURL,
+			ConfigCacheTTL: 5 * time.Minute,
+		},
+		Queue: QueueSettings{
+			Workers:           4,
+			Size:              100,
+			RetryAttempts:     5,
+			RetryBackoff:      time.Second,
+			RetryMaxBackoff:   30 * time.Second,
+			DeadLetterPath:    "dead-letters.jsonl",
+			DeliveryCacheSize: 10000,
+			DeliveryTTL:       24 * time.Hour,
+		},
+	}
+}
+
+// setting is a config value with its key in the file and its env-var
+type setting struct {
+	name   string
+	env    string
+	target interface{}
+}
+
+func (c *ServerConfig) settings() []setting {
+	return []setting{
+		{"server.host", "SERVER_HOST", &c.Server.Host},
+		{"server.port", "SERVER_PORT", &c.Server.Port},
+		{"server.read_timeout", "READ_TIMEOUT", &c.Server.ReadTimeout},
+		{"server.write_timeout", "WRITE_TIMEOUT", &c.Server.WriteTimeout},
+		{"server.shutdown_timeout", "SHUTDOWN_TIMEOUT", &c.Server.ShutdownTimeout},
+		{"server.drain_timeout", "DRAIN_TIMEOUT", &c.Server.DrainTimeout},
+		{"github.api_url", "GITHUB_API_URL", &c.GitHub.APIURL},
+		{"github.upload_url", "GITHUB_UPLOAD_URL", &c.GitHub.UploadURL},
+		{"github.application_id", "APPLICATION_ID", &c.GitHub.ApplicationID},
+		{"github.secret_path", "SECRET_PATH", &c.GitHub.SecretPath},
+		{"github.config_cache_ttl", "CONFIG_CACHE_TTL", &c.GitHub.ConfigCacheTTL},
+		{"queue.workers", "WORKER_COUNT", &c.Queue.Workers},
+		{"queue.size", "QUEUE_SIZE", &c.Queue.Size},
+		{"queue.retry_attempts", "RETRY_ATTEMPTS", &c.Queue.RetryAttempts},
+		{"queue.retry_backoff", "RETRY_BACKOFF", &c.Queue.RetryBackoff},
+		{"queue.retry_max_backoff", "RETRY_MAX_BACKOFF", &c.Queue.RetryMaxBackoff},
+		{"queue.dead_letter_path", "DEAD_LETTER_PATH", &c.Queue.DeadLetterPath},
+		{"queue.delivery_cache_size", "DELIVERY_CACHE_SIZE", &c.Queue.DeliveryCacheSize},
+		{"queue.delivery_ttl", "DELIVERY_TTL", &c.Queue.DeliveryTTL},
+		{"insecure_dev", "INSECURE_DEV", &c.InsecureDev},
+	}
+}
+
+/*
+LoadServerConfig reads the server config from the YAML file at path, an
+empty path only uses the defaults, and applies the env-var overrides.
+All problems with the config are returned in a single error
+*/
+func LoadServerConfig(path string) (ServerConfig, error) {
+	cfg := DefaultServerConfig()
+	if len(path) > 0 {
+		file, err := ioutil.ReadFile(path)
+		if err != nil {
+			return cfg, fmt.Errorf("unable to read server config: %v", err)
+		}
+		if err := yaml.UnmarshalStrict(file, &cfg); err != nil {
+			return cfg, fmt.Errorf("unable to parse server config %s: %v", path, err)
+		}
+	}
+	problems := cfg.applyEnv()
+	problems = append(problems, cfg.validate()...)
+	if len(problems) > 0 {
+		return cfg, fmt.Errorf("invalid server config:\n  - %s", strings.Join(problems, "\n  - "))
+	}
+	return cfg, nil
+}
+
+// applyEnv overrides settings with the env-vars that are set, empty
+// env-vars are treated as not set
+func (c *ServerConfig) applyEnv() []string {
+	var problems []string
+	for _, s := range c.settings() {
+		value := os.Getenv(s.env)
+		if len(value) == 0 {
+			continue
+		}
+		var err error
+		switch target := s.target.(type) {
+		case *string:
+			*target = value
+		case *int:
+			*target, err = strconv.Atoi(value)
+		case *time.Duration:
+			*target, err = time.ParseDuration(value)
+		case *bool:
+			*target, err = strconv.ParseBool(value)
+		}
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s has an invalid value %q", s.env, value))
+		}
+	}
+	return problems
+}
+
+// validate checks the settings, URLs are given a trailing slash
+func (c *ServerConfig) validate() []string {
+	var problems []string
+	for _, s := range c.settings() {
+		var problem string
+		switch target := s.target.(type) {
+		case *int:
+			if s.name == "server.port" && *target > 65535 {
+				problem = "must be a port number"
+			} else if *target < 1 {
+				problem = "must be a positive number"
+			}
+		case *time.Duration:
+			if *target <= 0 {
+				problem = "must be a positive duration such as 10s"
+			}
+		}
+		if len(problem) > 0 {
+			problems = append(problems, fmt.Sprintf("%s (%s) %s", s.name, s.env, problem))
+		}
+	}
+	if c.Queue.RetryMaxBackoff < c.Queue.RetryBackoff {
+		problems = append(problems, "queue.retry_max_backoff (RETRY_MAX_BACKOFF) must not be less than queue.retry_backoff")
+	}
+	if len(c.Queue.DeadLetterPath) == 0 {
+		problems = append(problems, "queue.dead_letter_path (DEAD_LETTER_PATH) must be set")
+	}
+	var err error
+	if c.GitHub.APIURL, err = normaliseURL(c.GitHub.APIURL); err != nil {
+		problems = append(problems, fmt.Sprintf("github.api_url (GITHUB_API_URL) %v", err))
+	}
+	if c.GitHub.UploadURL, err = normaliseURL(c.GitHub.UploadURL); err != nil {
+		problems = append(problems, fmt.Sprintf("github.upload_url (GITHUB_UPLOAD_URL) %v", err))
+	}
+	return problems
+}
+
+// normaliseURL checks a URL is absolute and ends it in a slash so paths
+// can be appended to it
+func normaliseURL(value string) (string, error) {
+	parsed, err := url.Parse(value)
+	if err != nil || !parsed.IsAbs() || parsed.Host == "" {
+		return value, fmt.Errorf("must be an absolute URL, got %q", value)
+	}
+	if !strings.HasSuffix(parsed.Path, "/") {
+		parsed.Path += "/"
+	}
+	return parsed.String(), nil
+}
+
+// current is the server config in use, it is replaced when it is reloaded
+var current struct {
+	sync.RWMutex
+	cfg *ServerConfig
+}
+
+// SetServerConfig makes cfg the server config in use
+func SetServerConfig(cfg ServerConfig) {
+	current.Lock()
+	defer current.Unlock()
+	current.cfg = &cfg
+}
+
+/*
+CurrentServerConfig returns the server config in use, until one is set
+the defaults and env-vars are used without being validated
+*/
+func CurrentServerConfig() ServerConfig {
+	current.RLock()
+	defer current.RUnlock()
+	if current.cfg != nil {
+		return *current.cfg
+	}
+	cfg := DefaultServerConfig()
+	cfg.applyEnv()
+	cfg.validate()
+	return cfg
+}
+
+/*
+RestartRequired lists the settings that changed between old and new that
+only take effect when Paul restarts
+*/
+func RestartRequired(old, new ServerConfig) []string {
+	var changed []string
+	if old.Server != new.Server {
+		changed = append(changed, "server")
+	}
+	if old.Queue != new.Queue {
+		changed = append(changed, "queue")
+	}
+	return changed
+}
@@ -0,0 +1,138 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeServerConfig(t *testing.T, contents string) (string, func()) {
+	tmpDir, err := ioutil.TempDir("", "paul-server-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	configPath := path.Join(tmpDir, "paul.yaml")
+	ioutil.WriteFile(configPath, []byte(contents), 0600)
+	return configPath, func() { os.RemoveAll(tmpDir) }
+}
+
+func TestLoadServerConfig(t *testing.T) {
+	t.Run("Test Defaults Are Used Without A File", func(t *testing.T) {
+		cfg, err := LoadServerConfig("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defaults := DefaultServerConfig()
+		if cfg.Server != defaults.Server || cfg.Queue != defaults.Queue {
+			t.Errorf("want %+v, got %+v", defaults, cfg)
+		}
+	})
+
+	t.Run("Test File Values Are Used", func(t *testing.T) {
+		configPath, cleanup := writeServerConfig(t, `
+server:
+  port: 9000
+  read_timeout: 30s
+github:
+  api_url: https://github.example.com/api/v3
+queue:
+  workers: 8
+`)
+		defer cleanup()
+		cfg, err := LoadServerConfig(configPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cfg.Server.Port != 9000 || cfg.Server.ReadTimeout != 30*time.Second || cfg.Queue.Workers != 8 {
+			t.Errorf("file values were not used: %+v", cfg)
+		}
+		if cfg.GitHub.APIURL != "https://github.example.com/api/v3/" {
+			t.Errorf("want %q, got %q", "https://github.example.com/api/v3/", cfg.GitHub.APIURL)
+		}
+		if cfg.Server.Host != "127.0.0.1" {
+			t.Errorf("want the default host, got %q", cfg.Server.Host)
+		}
+	})
+
+	t.Run("Test Env-Vars Override The File", func(t *testing.T) {
+		configPath, cleanup := writeServerConfig(t, "queue:\n  workers: 8\n")
+		defer cleanup()
+		os.Setenv("WORKER_COUNT", "2")
+		defer os.Unsetenv("WORKER_COUNT")
+		cfg, err := LoadServerConfig(configPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cfg.Queue.Workers != 2 {
+			t.Errorf("want 2 workers, got %d", cfg.Queue.Workers)
+		}
+	})
+
+	t.Run("Test Unknown Keys Are Refused", func(t *testing.T) {
+		configPath, cleanup := writeServerConfig(t, "queue:\n  worker: 8\n")
+		defer cleanup()
+		_, err := LoadServerConfig(configPath)
+		if err == nil || !strings.Contains(err.Error(), "field worker not found") {
+			t.Errorf("want an unknown field error, got %v", err)
+		}
+	})
+
+	t.Run("Test Every Problem Is Reported", func(t *testing.T) {
+		configPath, cleanup := writeServerConfig(t, `
+server:
+  port: 70000
+github:
+  api_url: github.example.com
+queue:
+  retry_backoff: 1m
+`)
+		defer cleanup()
+		os.Setenv("QUEUE_SIZE", "lots")
+		defer os.Unsetenv("QUEUE_SIZE")
+		_, err := LoadServerConfig(configPath)
+		if err == nil {
+			t.Fatal("want an error")
+		}
+		for _, want := range []string{
+			`QUEUE_SIZE has an invalid value "lots"`,
+			"server.port (SERVER_PORT) must be a port number",
+			"github.api_url (GITHUB_API_URL) must be an absolute URL",
+			"queue.retry_max_backoff (RETRY_MAX_BACKOFF) must not be less than queue.retry_backoff",
+		} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("want %q in %q", want, err.Error())
+			}
+		}
+	})
+}
+
+func TestCurrentServerConfig(t *testing.T) {
+	defer func() { current.cfg = nil }()
+	os.Setenv("APPLICATION_ID", "from-env")
+	defer os.Unsetenv("APPLICATION_ID")
+	if got := CurrentServerConfig().GitHub.ApplicationID; got != "from-env" {
+		t.Errorf("want env-vars to be used before a config is set, got %q", got)
+	}
+	cfg := DefaultServerConfig()
+	cfg.GitHub.ApplicationID = "from-file"
+	SetServerConfig(cfg)
+	if got := CurrentServerConfig().GitHub.ApplicationID; got != "from-file" {
+		t.Errorf("want the config that was set, got %q", got)
+	}
+}
+
+func TestRestartRequired(t *testing.T) {
+	old := DefaultServerConfig()
+	new := DefaultServerConfig()
+	new.GitHub.ApplicationID = "321"
+	if changed := RestartRequired(old, new); len(changed) != 0 {
+		t.Errorf("want no restart for github settings, got %q", changed)
+	}
+	new.Queue.Workers = 8
+	if changed := RestartRequired(old, new); len(changed) != 1 || changed[0] != "queue" {
+		t.Errorf("want a restart for queue settings, got %q", changed)
+	}
+}
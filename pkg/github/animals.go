@@ -0,0 +1,54 @@
+package github
+
+import (
+	"fmt"
+
+	"github.com/Spazzy757/paul/pkg/animals"
+)
+
+func init() {
+	commands.register(&Command{
+		Name:        "cat",
+		Description: "Posts a picture of a cat",
+		Permission:  PermissionAnyone,
+		ConfigKey:   "cat",
+		Handler: func(req *commandRequest) error {
+			return handleCats(req, animals.NewCatClient())
+		},
+	})
+	commands.register(&Command{
+		Name:        "dog",
+		Description: "Posts a picture of a dog",
+		Permission:  PermissionAnyone,
+		ConfigKey:   "dog",
+		Handler: func(req *commandRequest) error {
+			return handleDogs(req, animals.NewDogClient())
+		},
+	})
+}
+
+// handleCats is the handler for the /cat command
+func handleCats(
+	req *commandRequest,
+	catClient *animals.Client,
+) error {
+	cat, err := catClient.GetCat()
+	if err != nil {
+		return upstreamError(err)
+	}
+	message := fmt.Sprintf("My Most Trusted Minion\n\n ![my favorite minion](%v)", cat.Url)
+	return createComment(req, message)
+}
+
+// handleDogs is the handler for the /dog command
+func handleDogs(
+	req *commandRequest,
+	dogClient *animals.Client,
+) error {
+	dog, err := dogClient.GetDog()
+	if err != nil {
+		return upstreamError(err)
+	}
+	message := fmt.Sprintf("Despite how it looks it is well trained\n\n ![loyal soldier](%v)", dog.Url)
+	return createComment(req, message)
+}
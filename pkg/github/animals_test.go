@@ -0,0 +1,98 @@
+package github
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Spazzy757/paul/pkg/animals"
+	"github.com/Spazzy757/paul/pkg/helpers"
+	"github.com/Spazzy757/paul/pkg/types"
+	"github.com/google/go-github/v32/github"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleCats(t *testing.T) {
+	t.Run("Test Issue Comment Webhook is Handled correctly", func(t *testing.T) {
+		catAPIResponse := `[
+            {
+                "breeds":[],
+                "id":"40g",
+                "url":"https://cdn2.thecatapi.com/images/40g.jpg",
+                "width":640,
+                "height":426
+            }
+        ]`
+		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(catAPIResponse))
+		})
+		httpClient, teardown := helpers.MockHTTPClient(h)
+		defer teardown()
+
+		catClient := animals.NewCatClient()
+		catClient.HttpClient = httpClient
+		catClient.Url = "https://example.com"
+
+		mc := &mockIssueClient{
+			resp: &github.IssueComment{
+				ID: github.Int64(1),
+			},
+		}
+		req := getMockCommandRequest("cat-command", mc, &types.PaulConfig{})
+		if err := handleCats(req, catClient); err != nil {
+			t.Fatalf("comment on issue: %v", err)
+		}
+	})
+}
+
+func TestHandleDogs(t *testing.T) {
+	t.Run("Test Issue Comment Webhook is Handled correctly", func(t *testing.T) {
+		dogAPIResponse := `[
+            {
+                "breeds":[],
+                "id":"40g",
+                "url":"https://cdn2.thedogapi.com/images/40g.jpg",
+                "width":640,
+                "height":426
+            }
+        ]`
+		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(dogAPIResponse))
+		})
+		httpClient, teardown := helpers.MockHTTPClient(h)
+		defer teardown()
+
+		dogClient := animals.NewDogClient()
+		dogClient.HttpClient = httpClient
+		dogClient.Url = "https://example.com"
+
+		mc := &mockIssueClient{
+			resp: &github.IssueComment{
+				ID: github.Int64(1),
+			},
+		}
+		req := getMockCommandRequest("dog-command", mc, &types.PaulConfig{})
+		if err := handleDogs(req, dogClient); err != nil {
+			t.Fatalf("comment on issue: %v", err)
+		}
+	})
+}
+
+func TestHandleCatsUpstreamError(t *testing.T) {
+	t.Run("Test Cat API Failures Are Upstream Errors", func(t *testing.T) {
+		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("not json"))
+		})
+		httpClient, teardown := helpers.MockHTTPClient(h)
+		defer teardown()
+
+		catClient := animals.NewCatClient()
+		catClient.HttpClient = httpClient
+		catClient.Url = "https://example.com"
+
+		mc := &mockIssueClient{}
+		req := getMockCommandRequest("cat-command", mc, &types.PaulConfig{})
+		err := handleCats(req, catClient)
+		assert.Equal(t, ErrorKindUpstream, Classify(err))
+		assert.Empty(t, mc.comments)
+	})
+}
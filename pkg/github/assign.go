@@ -0,0 +1,136 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+)
+
+func init() {
+	commands.register(&Command{
+		Name:        "assign",
+		Args:        "[@user ...]",
+		Description: "Assigns users, defaults to yourself",
+		Permission:  PermissionAnyone,
+		ConfigKey:   "assign",
+		Handler:     handleAssign,
+	})
+	commands.register(&Command{
+		Name:        "unassign",
+		Args:        "[@user ...]",
+		Description: "Unassigns users, defaults to yourself",
+		Permission:  PermissionAnyone,
+		ConfigKey:   "assign",
+		Handler:     handleUnassign,
+	})
+}
+
+// handleAssign is the handler for the /assign command
+func handleAssign(req *commandRequest) error {
+	users, replies := assignableUsers(req)
+	var assignees []string
+	var strangers []string
+	for _, user := range users {
+		collaborator, _, err := req.repoClient.client.IsCollaborator(
+			req.repoClient.ctx,
+			req.owner,
+			req.repo,
+			user,
+		)
+		if err != nil {
+			return err
+		}
+		if collaborator {
+			assignees = append(assignees, user)
+		} else {
+			strangers = append(strangers, user)
+		}
+	}
+	if len(strangers) > 0 {
+		replies = append(replies, fmt.Sprintf(
+			"%v can not be assigned as only collaborators of this repository can be assigned",
+			mentionList(strangers),
+		))
+	}
+	if len(assignees) > 0 {
+		_, _, err := req.issueClient.client.AddAssignees(
+			req.issueClient.ctx,
+			req.owner,
+			req.repo,
+			req.number,
+			assignees,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	if len(replies) > 0 {
+		return createComment(req, strings.Join(replies, "\n\n"))
+	}
+	return nil
+}
+
+// handleUnassign is the handler for the /unassign command
+func handleUnassign(req *commandRequest) error {
+	users, replies := assignableUsers(req)
+	if len(users) > 0 {
+		_, _, err := req.issueClient.client.RemoveAssignees(
+			req.issueClient.ctx,
+			req.owner,
+			req.repo,
+			req.number,
+			users,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	if len(replies) > 0 {
+		return createComment(req, strings.Join(replies, "\n\n"))
+	}
+	return nil
+}
+
+/*
+assignableUsers works out which users the command applies to, defaulting
+to the commenter. Only maintainers can (un)assign other users, anyone else
+is limited to themselves and gets a reply explaining why
+*/
+func assignableUsers(req *commandRequest) ([]string, []string) {
+	var users, others, replies []string
+	requested := req.args
+	if len(requested) == 0 {
+		requested = []string{req.user}
+	}
+	maintainer := req.isMaintainer(req.user)
+	for _, user := range requested {
+		user = strings.TrimPrefix(user, "@")
+		if user == "me" {
+			user = req.user
+		}
+		if user == "" {
+			continue
+		}
+		if !maintainer && !strings.EqualFold(user, req.user) {
+			others = append(others, user)
+			continue
+		}
+		users = append(users, user)
+	}
+	if len(others) > 0 {
+		replies = append(replies, fmt.Sprintf(
+			"Sorry @%v, only maintainers can assign or unassign other users (%v)",
+			req.user,
+			mentionList(others),
+		))
+	}
+	return users, replies
+}
+
+// mentionList formats users as a comma separated list of mentions
+func mentionList(users []string) string {
+	mentions := make([]string, len(users))
+	for i, user := range users {
+		mentions[i] = "@" + user
+	}
+	return strings.Join(mentions, ", ")
+}
@@ -0,0 +1,58 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleAssign(t *testing.T) {
+	permissions := map[string]string{
+		"Spazzy757":    "admin",
+		"collaborator": "write",
+	}
+	t.Run("Test Defaults To The Commenter", func(t *testing.T) {
+		req := getMockPermissionRequest("collaborator", "author", permissions)
+		mc := req.issueClient.client.(*mockIssueClient)
+		assert.Nil(t, handleAssign(req))
+		assert.Equal(t, []string{"collaborator"}, mc.assignees)
+		assert.Empty(t, mc.comments)
+	})
+	t.Run("Test Non Maintainers Can Only Assign Themselves", func(t *testing.T) {
+		req := getMockPermissionRequest("collaborator", "author", permissions)
+		req.args = []string{"me", "@Spazzy757"}
+		mc := req.issueClient.client.(*mockIssueClient)
+		assert.Nil(t, handleAssign(req))
+		assert.Equal(t, []string{"collaborator"}, mc.assignees)
+		assert.Len(t, mc.comments, 1)
+		assert.Contains(t, mc.comments[0], "only maintainers can assign")
+	})
+	t.Run("Test Maintainers Can Assign Collaborators", func(t *testing.T) {
+		req := getMockPermissionRequest("Spazzy757", "author", permissions)
+		req.args = []string{"@collaborator", "@stranger"}
+		mc := req.issueClient.client.(*mockIssueClient)
+		assert.Nil(t, handleAssign(req))
+		assert.Equal(t, []string{"collaborator"}, mc.assignees)
+		assert.Len(t, mc.comments, 1)
+		assert.Contains(t, mc.comments[0], "@stranger can not be assigned")
+	})
+}
+
+func TestHandleUnassign(t *testing.T) {
+	t.Run("Test Defaults To The Commenter", func(t *testing.T) {
+		req := getMockPermissionRequest("collaborator", "author", nil)
+		mc := req.issueClient.client.(*mockIssueClient)
+		mc.assignees = []string{"collaborator", "Spazzy757"}
+		assert.Nil(t, handleUnassign(req))
+		assert.Equal(t, []string{"Spazzy757"}, mc.assignees)
+	})
+	t.Run("Test Non Maintainers Can Not Unassign Others", func(t *testing.T) {
+		req := getMockPermissionRequest("collaborator", "author", nil)
+		req.args = []string{"@Spazzy757"}
+		mc := req.issueClient.client.(*mockIssueClient)
+		mc.assignees = []string{"Spazzy757"}
+		assert.Nil(t, handleUnassign(req))
+		assert.Equal(t, []string{"Spazzy757"}, mc.assignees)
+		assert.Len(t, mc.comments, 1)
+	})
+}
@@ -8,58 +8,116 @@ import (
 	"github.com/Spazzy757/paul/pkg/types"
 	"github.com/google/go-github/v32/github"
 	"golang.org/x/oauth2"
-	"io/ioutil"
-	"log"
+	"net/http"
+	"net/url"
+	"sync"
 )
 
-const configFile = "PAUL.yaml"
+// app caches the app config and installation tokens, they are loaded
+// the first time a client is needed
+var app struct {
+	sync.Mutex
+	config *config.Config
+	tokens *helpers.TokenManager
+}
+
+// appConfig returns the app config and token manager, the app config is
+// only read from disk until it loads successfully
+func appConfig() (config.Config, *helpers.TokenManager, error) {
+	app.Lock()
+	defer app.Unlock()
+	if app.config == nil {
+		cfg, err := config.NewConfig()
+		if err != nil {
+			return cfg, nil, fmt.Errorf("can't load config: %v", err)
+		}
+		app.config = &cfg
+		app.tokens = helpers.NewTokenManager(cfg)
+	}
+	return *app.config, app.tokens, nil
+}
 
-func getClient(installationId int64) (*github.Client, context.Context) {
-	cfg, err := config.NewConfig()
+// ReloadAppConfig drops the cached app config and installation tokens so
+// they are loaded again with the current server config
+func ReloadAppConfig() {
+	app.Lock()
+	defer app.Unlock()
+	app.config = nil
+	app.tokens = nil
+}
+
+func getClient(installationId int64) (*github.Client, context.Context, error) {
+	cfg, manager, err := appConfig()
 	if err != nil {
-		log.Fatalf("Can't load config: %v", err)
+		return nil, nil, err
 	}
-	token, tokenErr := helpers.GetAccessToken(cfg, installationId)
-	if tokenErr != nil {
-		log.Fatalf("Can't load config: %v", err)
+	// fetch the token up front so failures are reported as GitHub errors
+	if _, tokenErr := manager.Token(installationId); tokenErr != nil {
+		return nil, nil, githubError(fmt.Errorf("can't get access token: %v", tokenErr))
 	}
 	ctx := context.Background()
-	ts := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: token},
-	)
-	tc := oauth2.NewClient(ctx, ts)
+	tc := oauth2.NewClient(ctx, manager.TokenSource(installationId))
 
-	client := github.NewClient(tc)
-	return client, ctx
+	client, err := newGithubClient(cfg, tc)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, ctx, nil
 }
 
-// TODO: Move This Logic into configs
-func getPaulConfig(
-	owner, repo *string,
-	client *github.Client,
-	contentUrl string,
-	ctx context.Context,
-) (types.PaulConfig, error) {
-	var paulCfg types.PaulConfig
+/*
+newGithubClient creates a client for the API in the app config, the URLs
+are used as given so they match the URL installation tokens are minted
+with, GitHub Enterprise Server URLs have to include /api/v3/
+*/
+func newGithubClient(cfg config.Config, httpClient *http.Client) (*github.Client, error) {
+	client := github.NewClient(httpClient)
+	if cfg.APIURL != "" {
+		baseURL, err := url.Parse(cfg.APIURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid GitHub API URL: %v", err)
+		}
+		client.BaseURL = baseURL
+	}
+	if cfg.UploadURL != "" {
+		uploadURL, err := url.Parse(cfg.UploadURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid GitHub upload URL: %v", err)
+		}
+		client.UploadURL = uploadURL
+	}
+	return client, nil
+}
 
-	response, err := client.Repositories.DownloadContents(
-		ctx,
-		*owner,
-		*repo,
-		configFile,
-		&github.RepositoryContentGetOptions{
-			Ref: "main",
-		},
-	)
+// getRepoClient loads the client for an installation and the Paul Config of the repo
+func getRepoClient(
+	installationID int64,
+	repo *github.Repository,
+) (*github.Client, context.Context, types.PaulConfig, error) {
+	client, ctx, err := getClient(installationID)
 	if err != nil {
-		return paulCfg, fmt.Errorf("unable to download config file: %s", err)
+		return nil, nil, types.PaulConfig{}, err
 	}
-	defer response.Close()
-
-	bytesConfig, err := ioutil.ReadAll(response)
+	cfg, err := getPaulConfig(ctx, client, repo)
 	if err != nil {
-		return paulCfg, fmt.Errorf("unable to read github's response: %s", err)
+		return nil, nil, cfg, err
 	}
-	paulCfg.LoadConfig(bytesConfig)
-	return paulCfg, nil
+	return client, ctx, cfg, nil
+}
+
+// getPaulConfig loads the PAUL.yaml on the default branch of a repo
+// through the config cache
+func getPaulConfig(
+	ctx context.Context,
+	client *github.Client,
+	repo *github.Repository,
+) (types.PaulConfig, error) {
+	return repoConfigs.get(
+		ctx,
+		client,
+		repo.GetOwner().GetLogin(),
+		repo.GetName(),
+		repo.GetDefaultBranch(),
+		configTTL(),
+	)
 }
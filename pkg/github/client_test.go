@@ -0,0 +1,27 @@
+package github
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Spazzy757/paul/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewGithubClient(t *testing.T) {
+	t.Run("Test github.com Is Used By Default", func(t *testing.T) {
+		client, err := newGithubClient(config.Config{}, http.DefaultClient)
+		assert.Nil(t, err)
+		assert.Equal(t, config.DefaultAPIURL, client.BaseURL.String())
+		assert.Equal(t, config.DefaultUploadURL, client.UploadURL.String())
+	})
+	t.Run("Test Enterprise URLs Are Used As Given", func(t *testing.T) {
+		client, err := newGithubClient(config.Config{
+			APIURL:    "https://github.example.com/api/v3/",
+			UploadURL: "https://github.example.com/api/uploads/",
+		}, http.DefaultClient)
+		assert.Nil(t, err)
+		assert.Equal(t, "https://github.example.com/api/v3/", client.BaseURL.String())
+		assert.Equal(t, "https://github.example.com/api/uploads/", client.UploadURL.String())
+	})
+}
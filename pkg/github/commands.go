@@ -0,0 +1,221 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/Spazzy757/paul/pkg/types"
+	"github.com/google/go-github/v32/github"
+)
+
+// Permission defines who is allowed to run a command
+type Permission int
+
+const (
+	// PermissionAnyone allows any user to run the command
+	PermissionAnyone Permission = iota
+)
+
+// CommandHandler runs the logic of a command
+type CommandHandler func(req *commandRequest) error
+
+// Command defines a slash command that can be run from a comment
+type Command struct {
+	// Name is the command without the leading slash e.g. cat for /cat
+	Name string
+	// Aliases are alternative names that run the same command
+	Aliases []string
+	// Args describes the arguments the command takes, used in help text
+	Args string
+	// Description is a short explanation of the command, used in help text
+	Description string
+	// Permission is who is allowed to run the command
+	Permission Permission
+	// ConfigKey is the key under commands in PAUL.yaml that enables
+	// the command, commands without a key are always enabled
+	ConfigKey string
+	// Handler is the function run when the command is invoked
+	Handler CommandHandler
+}
+
+// Enabled checks the repo config to see if the command can be run
+func (c *Command) Enabled(cfg *types.PaulConfig) bool {
+	if c.ConfigKey == "" {
+		return true
+	}
+	return cfg.CommandEnabled(c.ConfigKey)
+}
+
+// commandClients groups the clients handlers use to talk to GitHub
+type commandClients struct {
+	issueClient       *issueClient
+	repoClient        *repositoryClient
+	pullRequestClient *pullRequestClient
+	checksClient      *checksClient
+	reactionClient    *reactionClient
+}
+
+// newCommandClients creates the clients used by handlers from a GitHub client
+func newCommandClients(client *github.Client, ctx context.Context) commandClients {
+	return commandClients{
+		issueClient:       &issueClient{ctx: ctx, client: client.Issues},
+		repoClient:        &repositoryClient{ctx: ctx, client: client.Repositories},
+		pullRequestClient: &pullRequestClient{ctx: ctx, client: client.PullRequests},
+		checksClient:      &checksClient{ctx: ctx, client: client.Checks},
+		reactionClient:    &reactionClient{ctx: ctx, client: client.Reactions},
+	}
+}
+
+// commandRequest holds everything a handler needs to run a command
+type commandRequest struct {
+	commandClients
+	owner       string
+	repo        string
+	number      int
+	commentID   int64
+	commentKind commentKind
+	user        string
+	issueAuthor string
+	pullRequest bool
+	args        []string
+	line        string
+	config      *types.PaulConfig
+}
+
+// newCommandRequest builds a commandRequest from an IssueCommentEvent
+func newCommandRequest(
+	event *github.IssueCommentEvent,
+	clients commandClients,
+	cfg *types.PaulConfig,
+	inv invocation,
+) *commandRequest {
+	return &commandRequest{
+		commandClients: clients,
+		owner:          event.Repo.Owner.GetLogin(),
+		repo:           event.Repo.GetName(),
+		number:         event.Issue.GetNumber(),
+		commentID:      event.Comment.GetID(),
+		user:           commandUser(event.GetAction(), event.Comment.User, event.GetSender()),
+		issueAuthor:    event.Issue.User.GetLogin(),
+		pullRequest:    event.Issue.IsPullRequest(),
+		args:           inv.args,
+		line:           inv.line,
+		config:         cfg,
+	}
+}
+
+/*
+commandUser is who runs the commands in a comment, commands added by an
+edit run as whoever edited the comment so they can not borrow the
+permissions of the comment's author
+*/
+func commandUser(action string, author, sender *github.User) string {
+	if action == "edited" {
+		return sender.GetLogin()
+	}
+	return author.GetLogin()
+}
+
+/*
+runComment runs every command in a comment body that has not run for the
+comment yet, in the order they were given. base is copied for every
+command with the arguments of that command. Commands are only remembered
+once they succeed so a retried webhook runs the failed command and the
+ones after it again
+*/
+func runComment(base *commandRequest, key, body, previous string) error {
+	for _, cmd := range history.pending(key, body, previous) {
+		req := *base
+		req.args = cmd.args
+		req.line = cmd.line
+		if err := runWithReactions(commands, cmd.name, &req); err != nil {
+			return fmt.Errorf("/%v: %w", cmd.name, err)
+		}
+		history.done(key, cmd)
+	}
+	return nil
+}
+
+// registry keeps track of all commands Paul knows about
+type registry struct {
+	commands []*Command
+	index    map[string]*Command
+}
+
+func newRegistry() *registry {
+	return &registry{index: map[string]*Command{}}
+}
+
+// commands is the registry used by the webhook handlers,
+// commands add themselves to it in their own files
+var commands = newRegistry()
+
+// register adds a command to the registry, it panics if the name or
+// one of the aliases are already taken as that is a programming error
+func (r *registry) register(cmd *Command) {
+	for _, name := range append([]string{cmd.Name}, cmd.Aliases...) {
+		if _, ok := r.index[name]; ok {
+			panic(fmt.Sprintf("command %q registered twice", name))
+		}
+		r.index[name] = cmd
+	}
+	r.commands = append(r.commands, cmd)
+}
+
+// lookup finds a command by its name or alias
+func (r *registry) lookup(name string) (*Command, bool) {
+	cmd, ok := r.index[name]
+	return cmd, ok
+}
+
+// enabled returns the commands enabled by the repo config sorted by name
+func (r *registry) enabled(cfg *types.PaulConfig) []*Command {
+	var cmds []*Command
+	for _, cmd := range r.commands {
+		if cmd.Enabled(cfg) {
+			cmds = append(cmds, cmd)
+		}
+	}
+	sort.Slice(cmds, func(i, j int) bool {
+		return cmds[i].Name < cmds[j].Name
+	})
+	return cmds
+}
+
+// resolve looks up a command that is enabled by the repo config
+func (r *registry) resolve(name string, cfg *types.PaulConfig) (*Command, bool) {
+	cmd, ok := r.lookup(name)
+	if !ok || !cmd.Enabled(cfg) {
+		return nil, false
+	}
+	return cmd, true
+}
+
+/*
+run looks up the command and runs it if it is enabled and the user is
+allowed to run it, unknown and disabled commands are ignored
+*/
+func (r *registry) run(name string, req *commandRequest) error {
+	cmd, ok := r.resolve(name, req.config)
+	if !ok {
+		return nil
+	}
+	_, err := cmd.execute(req)
+	return err
+}
+
+/*
+execute runs the handler if the user is allowed to run the command,
+users that are not allowed get a reply and false is returned
+*/
+func (c *Command) execute(req *commandRequest) (bool, error) {
+	allowed, err := req.allowed(c.Permission)
+	if err != nil {
+		return false, err
+	}
+	if !allowed {
+		return false, denied(req, c)
+	}
+	return true, c.Handler(req)
+}
@@ -0,0 +1,138 @@
+package github
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Spazzy757/paul/pkg/types"
+	"github.com/google/go-github/v32/github"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	ran := []string{}
+	r := newRegistry()
+	r.register(&Command{
+		Name:    "always",
+		Aliases: []string{"a"},
+		Handler: func(req *commandRequest) error {
+			ran = append(ran, "always")
+			return nil
+		},
+	})
+	r.register(&Command{
+		Name:      "configured",
+		ConfigKey: "configured",
+		Handler: func(req *commandRequest) error {
+			ran = append(ran, "configured")
+			return nil
+		},
+	})
+	t.Run("Test Lookup By Name And Alias", func(t *testing.T) {
+		byName, ok := r.lookup("always")
+		assert.True(t, ok)
+		byAlias, ok := r.lookup("a")
+		assert.True(t, ok)
+		assert.Equal(t, byName, byAlias)
+		_, ok = r.lookup("missing")
+		assert.False(t, ok)
+	})
+	t.Run("Test Registering A Taken Name Panics", func(t *testing.T) {
+		assert.Panics(t, func() {
+			r.register(&Command{Name: "a"})
+		})
+	})
+	t.Run("Test Enabled Follows Config", func(t *testing.T) {
+		cfg := &types.PaulConfig{}
+		assert.Len(t, r.enabled(cfg), 1)
+		cfg.Commands = map[string]bool{"configured": true}
+		assert.Len(t, r.enabled(cfg), 2)
+	})
+	t.Run("Test Run Skips Disabled And Unknown Commands", func(t *testing.T) {
+		ran = []string{}
+		req := &commandRequest{config: &types.PaulConfig{}}
+		assert.Nil(t, r.run("configured", req))
+		assert.Nil(t, r.run("missing", req))
+		assert.Nil(t, r.run("a", req))
+		assert.Equal(t, []string{"always"}, ran)
+	})
+}
+
+func TestDefaultCommands(t *testing.T) {
+	t.Run("Test Animal Commands Are Registered", func(t *testing.T) {
+		cfg := &types.PaulConfig{
+			PullRequests: types.PullRequests{CatsEnabled: true, DogsEnabled: true},
+		}
+		for _, name := range []string{"cat", "dog"} {
+			cmd, ok := commands.lookup(name)
+			assert.True(t, ok)
+			assert.True(t, cmd.Enabled(cfg))
+		}
+	})
+}
+
+func TestRunComment(t *testing.T) {
+	defer func(r *registry, h *commandHistory) { commands, history = r, h }(commands, history)
+	t.Run("Test Failed Commands Run Again When Retried", func(t *testing.T) {
+		history = newCommandHistory(historyLimit)
+		commands = newRegistry()
+		ran := []string{}
+		fail := true
+		commands.register(&Command{
+			Name: "flaky",
+			Handler: func(req *commandRequest) error {
+				if fail {
+					fail = false
+					return fmt.Errorf("github is down")
+				}
+				ran = append(ran, "flaky")
+				return nil
+			},
+		})
+		commands.register(&Command{
+			Name: "after",
+			Handler: func(req *commandRequest) error {
+				ran = append(ran, "after")
+				return nil
+			},
+		})
+		req := &commandRequest{config: &types.PaulConfig{}}
+		key := commentKey("issue_comment", 1)
+		assert.NotNil(t, runComment(req, key, "/flaky\n/after", ""))
+		assert.Empty(t, ran)
+		assert.Nil(t, runComment(req, key, "/flaky\n/after", ""))
+		assert.Equal(t, []string{"flaky", "after"}, ran)
+		assert.Nil(t, runComment(req, key, "/flaky\n/after", ""))
+		assert.Equal(t, []string{"flaky", "after"}, ran)
+	})
+}
+
+func TestCommandUser(t *testing.T) {
+	event := &github.IssueCommentEvent{
+		Action:  github.String("edited"),
+		Repo:    &github.Repository{Name: github.String("paul"), Owner: &github.User{Login: github.String("Spazzy757")}},
+		Issue:   &github.Issue{Number: github.Int(1), User: &github.User{Login: github.String("author")}},
+		Comment: &github.IssueComment{ID: github.Int64(1), User: &github.User{Login: github.String("maintainer")}},
+		Sender:  &github.User{Login: github.String("collaborator")},
+	}
+	t.Run("Test Edited Comments Run As The Editor", func(t *testing.T) {
+		mc := &mockIssueClient{}
+		cfg := &types.PaulConfig{Maintainers: []string{"maintainer"}}
+		req := newCommandRequest(event, getMockClients(mc, &mockReactionClient{}), cfg, invocation{})
+		assert.Equal(t, "collaborator", req.user)
+		cmd := &Command{Name: "maintainers-only", Permission: PermissionMaintainer, Handler: func(req *commandRequest) error {
+			t.Fatal("handler ran with the permissions of the comment's author")
+			return nil
+		}}
+		allowed, err := cmd.execute(req)
+		assert.Nil(t, err)
+		assert.False(t, allowed)
+		assert.Equal(t, []string{"Sorry @collaborator, only maintainers can run `/maintainers-only` here"}, mc.comments)
+	})
+	t.Run("Test Created Comments Run As The Author", func(t *testing.T) {
+		created := *event
+		created.Action = github.String("created")
+		req := newCommandRequest(&created, commandClients{}, &types.PaulConfig{}, invocation{})
+		assert.Equal(t, "maintainer", req.user)
+	})
+}
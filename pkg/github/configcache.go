@@ -0,0 +1,232 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Spazzy757/paul/pkg/config"
+	"github.com/Spazzy757/paul/pkg/types"
+	"github.com/google/go-github/v32/github"
+)
+
+// configFiles are where PAUL.yaml is looked for in order
+var configFiles = []string{"PAUL.yaml", ".github/PAUL.yaml", ".paul.yaml"}
+
+/*
+configCacheMetrics counts how PAUL.yaml was loaded, hits are served from
+the cache or revalidated with a conditional request that does not count
+against the rate limit, misses are downloaded
+*/
+var configCacheMetrics = expvar.NewMap("paul_config_cache")
+
+func init() {
+	configCacheMetrics.Set("hit_rate", expvar.Func(func() interface{} {
+		hits := metricValue("hits")
+		total := hits + metricValue("misses")
+		if total == 0 {
+			return 0.0
+		}
+		return float64(hits) / float64(total)
+	}))
+}
+
+func metricValue(name string) int64 {
+	if v, ok := configCacheMetrics.Get(name).(*expvar.Int); ok {
+		return v.Value()
+	}
+	return 0
+}
+
+// cachedConfig is a PAUL.yaml with where it was found and the ETag it was
+// downloaded with, repos without a PAUL.yaml have no path
+type cachedConfig struct {
+	config  types.PaulConfig
+	path    string
+	etag    string
+	checked time.Time
+}
+
+// configCache keeps the PAUL.yaml of every repo Paul has seen
+type configCache struct {
+	sync.Mutex
+	entries map[string]*cachedConfig
+	now     func() time.Time
+}
+
+func newConfigCache() *configCache {
+	return &configCache{entries: map[string]*cachedConfig{}, now: time.Now}
+}
+
+// repoConfigs is used by the webhook handlers to load PAUL.yaml
+var repoConfigs = newConfigCache()
+
+func configCacheKey(owner, repo, branch string) string {
+	return fmt.Sprintf("%v@%v", strings.ToLower(fmt.Sprintf("%v/%v", owner, repo)), branch)
+}
+
+/*
+get returns the PAUL.yaml on a branch of a repo, configs checked within
+the TTL are used as they are and older configs are checked again with
+their ETag so they are only downloaded again when they changed. Repos
+without a PAUL.yaml use the defaults
+*/
+func (c *configCache) get(
+	ctx context.Context,
+	client *github.Client,
+	owner, repo, branch string,
+	ttl time.Duration,
+) (types.PaulConfig, error) {
+	key := configCacheKey(owner, repo, branch)
+	c.Lock()
+	cached, ok := c.entries[key]
+	if ok && c.now().Sub(cached.checked) < ttl {
+		c.Unlock()
+		configCacheMetrics.Add("hits", 1)
+		return cached.config, nil
+	}
+	c.Unlock()
+
+	if ok && cached.path != "" {
+		body, etag, err := downloadConfig(ctx, client, owner, repo, branch, cached.path, cached.etag)
+		switch {
+		case errors.Is(err, errNotModified):
+			c.Lock()
+			cached.checked = c.now()
+			c.Unlock()
+			configCacheMetrics.Add("hits", 1)
+			return cached.config, nil
+		case err == nil:
+			return c.store(key, cached.path, etag, body)
+		case !errors.Is(err, errConfigNotFound):
+			configCacheMetrics.Add("misses", 1)
+			return types.PaulConfig{}, err
+		}
+	}
+	// look for the config as it is new or was moved
+	for _, path := range configFiles {
+		body, etag, err := downloadConfig(ctx, client, owner, repo, branch, path, "")
+		if errors.Is(err, errConfigNotFound) {
+			continue
+		}
+		if err != nil {
+			configCacheMetrics.Add("misses", 1)
+			return types.PaulConfig{}, err
+		}
+		return c.store(key, path, etag, body)
+	}
+	return c.store(key, "", "", nil)
+}
+
+// store parses a downloaded config and caches it, configs that can not
+// be parsed are not cached so they are downloaded again once fixed
+func (c *configCache) store(key, path, etag string, body []byte) (types.PaulConfig, error) {
+	configCacheMetrics.Add("misses", 1)
+	var paulCfg types.PaulConfig
+	if err := paulCfg.LoadConfig(body); err != nil {
+		c.Lock()
+		delete(c.entries, key)
+		c.Unlock()
+		return paulCfg, configError(fmt.Errorf("%v: %w", path, err))
+	}
+	c.Lock()
+	c.entries[key] = &cachedConfig{config: paulCfg, path: path, etag: etag, checked: c.now()}
+	c.Unlock()
+	return paulCfg, nil
+}
+
+// invalidate forgets the PAUL.yaml of a branch so it is downloaded again
+func (c *configCache) invalidate(owner, repo, branch string) {
+	c.Lock()
+	defer c.Unlock()
+	key := configCacheKey(owner, repo, branch)
+	if _, ok := c.entries[key]; ok {
+		delete(c.entries, key)
+		configCacheMetrics.Add("invalidations", 1)
+	}
+}
+
+var (
+	// errNotModified is returned when PAUL.yaml still matches the ETag
+	errNotModified = errors.New("config not modified")
+	// errConfigNotFound is returned when there is no PAUL.yaml at a path
+	errConfigNotFound = errors.New("config not found")
+)
+
+/*
+downloadConfig downloads the file at path on a branch, the default branch
+is used when branch is empty. When etag is given the file is only
+downloaded if it changed and errNotModified is returned otherwise
+*/
+func downloadConfig(
+	ctx context.Context,
+	client *github.Client,
+	owner, repo, branch, path, etag string,
+) ([]byte, string, error) {
+	u := fmt.Sprintf("repos/%v/%v/contents/%v", owner, repo, path)
+	if branch != "" {
+		u += "?ref=" + url.QueryEscape(branch)
+	}
+	req, err := client.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	content := new(github.RepositoryContent)
+	resp, err := client.Do(ctx, req, content)
+	if resp != nil && resp.StatusCode == http.StatusNotModified {
+		return nil, "", errNotModified
+	}
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return nil, "", errConfigNotFound
+	}
+	if err != nil {
+		return nil, "", githubError(fmt.Errorf("unable to download config file: %s", err))
+	}
+	body, err := content.GetContent()
+	if err != nil {
+		return nil, "", githubError(fmt.Errorf("unable to read github's response: %s", err))
+	}
+	return []byte(body), resp.Header.Get("ETag"), nil
+}
+
+// configTTL is how long PAUL.yaml is used before checking it changed
+func configTTL() time.Duration {
+	return config.CurrentServerConfig().GitHub.ConfigCacheTTL
+}
+
+// PushHandler forgets the PAUL.yaml of a repo when a push to the default
+// branch changes it
+func PushHandler(event *github.PushEvent) error {
+	repo := event.GetRepo()
+	branch := repo.GetDefaultBranch()
+	if event.GetRef() != "refs/heads/"+branch || !touchesConfig(event) {
+		return nil
+	}
+	repoConfigs.invalidate(repo.GetOwner().GetLogin(), repo.GetName(), branch)
+	return nil
+}
+
+// touchesConfig checks if any commit in a push changed a PAUL.yaml
+func touchesConfig(event *github.PushEvent) bool {
+	for _, commit := range event.Commits {
+		for _, files := range [][]string{commit.Added, commit.Modified, commit.Removed} {
+			for _, file := range files {
+				for _, path := range configFiles {
+					if file == path {
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
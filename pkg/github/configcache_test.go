@@ -0,0 +1,199 @@
+package github
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Spazzy757/paul/pkg/config"
+	"github.com/Spazzy757/paul/pkg/types"
+	"github.com/google/go-github/v32/github"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockContentsServer struct {
+	path     string
+	body     string
+	etag     string
+	requests []string
+}
+
+func (m *mockContentsServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.requests = append(m.requests, r.Header.Get("If-None-Match"))
+	path := m.path
+	if path == "" {
+		path = "PAUL.yaml"
+	}
+	if r.URL.Path != "/repos/Spazzy757/paul/contents/"+path || r.URL.Query().Get("ref") != "master" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if r.Header.Get("If-None-Match") == m.etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("ETag", m.etag)
+	fmt.Fprintf(w, `{"type":"file","encoding":"base64","content":%q}`,
+		base64.StdEncoding.EncodeToString([]byte(m.body)))
+}
+
+func getMockContentsClient(t *testing.T, m *mockContentsServer) (*github.Client, func()) {
+	server := httptest.NewServer(m)
+	client, err := newGithubClient(config.Config{APIURL: server.URL + "/"}, server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, server.Close
+}
+
+func TestConfigCache(t *testing.T) {
+	ctx := context.Background()
+	t.Run("Test Configs Are Cached For The TTL", func(t *testing.T) {
+		m := &mockContentsServer{body: "maintainers:\n- Spazzy757\n", etag: `"v1"`}
+		client, cleanup := getMockContentsClient(t, m)
+		defer cleanup()
+		now := time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)
+		cache := newConfigCache()
+		cache.now = func() time.Time { return now }
+
+		cfg, err := cache.get(ctx, client, "Spazzy757", "paul", "master", time.Minute)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"Spazzy757"}, cfg.Maintainers)
+		now = now.Add(30 * time.Second)
+		cfg, err = cache.get(ctx, client, "spazzy757", "Paul", "master", time.Minute)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"Spazzy757"}, cfg.Maintainers)
+		assert.Equal(t, []string{""}, m.requests)
+	})
+	t.Run("Test Expired Configs Are Revalidated With Their ETag", func(t *testing.T) {
+		m := &mockContentsServer{body: "maintainers:\n- Spazzy757\n", etag: `"v1"`}
+		client, cleanup := getMockContentsClient(t, m)
+		defer cleanup()
+		now := time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)
+		cache := newConfigCache()
+		cache.now = func() time.Time { return now }
+
+		cache.get(ctx, client, "Spazzy757", "paul", "master", time.Minute)
+		now = now.Add(2 * time.Minute)
+		cfg, err := cache.get(ctx, client, "Spazzy757", "paul", "master", time.Minute)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"Spazzy757"}, cfg.Maintainers)
+
+		m.body = "maintainers:\n- someone\n"
+		m.etag = `"v2"`
+		now = now.Add(2 * time.Minute)
+		cfg, err = cache.get(ctx, client, "Spazzy757", "paul", "master", time.Minute)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"someone"}, cfg.Maintainers)
+		assert.Equal(t, []string{"", `"v1"`, `"v1"`}, m.requests)
+	})
+	t.Run("Test Invalidated Configs Are Downloaded Again", func(t *testing.T) {
+		m := &mockContentsServer{body: "maintainers:\n- Spazzy757\n", etag: `"v1"`}
+		client, cleanup := getMockContentsClient(t, m)
+		defer cleanup()
+		cache := newConfigCache()
+		cache.get(ctx, client, "Spazzy757", "paul", "master", time.Hour)
+		cache.invalidate("Spazzy757", "paul", "master")
+		cache.get(ctx, client, "Spazzy757", "paul", "master", time.Hour)
+		assert.Equal(t, []string{"", ""}, m.requests)
+	})
+	t.Run("Test Broken Configs Are Not Cached", func(t *testing.T) {
+		m := &mockContentsServer{body: "maintainers: [", etag: `"v1"`}
+		client, cleanup := getMockContentsClient(t, m)
+		defer cleanup()
+		cache := newConfigCache()
+		_, err := cache.get(ctx, client, "Spazzy757", "paul", "master", time.Hour)
+		assert.Equal(t, ErrorKindConfig, Classify(err))
+		m.body = "maintainers:\n- Spazzy757\n"
+		m.etag = `"v2"`
+		cfg, err := cache.get(ctx, client, "Spazzy757", "paul", "master", time.Hour)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"Spazzy757"}, cfg.Maintainers)
+	})
+	t.Run("Test Other Config Locations Are Used", func(t *testing.T) {
+		m := &mockContentsServer{path: ".github/PAUL.yaml", body: "maintainers:\n- Spazzy757\n", etag: `"v1"`}
+		client, cleanup := getMockContentsClient(t, m)
+		defer cleanup()
+		cache := newConfigCache()
+		cfg, err := cache.get(ctx, client, "Spazzy757", "paul", "master", time.Hour)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"Spazzy757"}, cfg.Maintainers)
+		assert.Equal(t, ".github/PAUL.yaml", cache.entries[configCacheKey("Spazzy757", "paul", "master")].path)
+	})
+	t.Run("Test Moved Configs Are Found Again", func(t *testing.T) {
+		m := &mockContentsServer{body: "maintainers:\n- Spazzy757\n", etag: `"v1"`}
+		client, cleanup := getMockContentsClient(t, m)
+		defer cleanup()
+		now := time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)
+		cache := newConfigCache()
+		cache.now = func() time.Time { return now }
+		cache.get(ctx, client, "Spazzy757", "paul", "master", time.Minute)
+		m.path = ".paul.yaml"
+		m.body = "maintainers:\n- someone\n"
+		now = now.Add(2 * time.Minute)
+		cfg, err := cache.get(ctx, client, "Spazzy757", "paul", "master", time.Minute)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"someone"}, cfg.Maintainers)
+	})
+	t.Run("Test Missing Configs Use The Defaults", func(t *testing.T) {
+		m := &mockContentsServer{}
+		client, cleanup := getMockContentsClient(t, m)
+		defer cleanup()
+		cache := newConfigCache()
+		cfg, err := cache.get(ctx, client, "Spazzy757", "paul", "main", time.Hour)
+		assert.Nil(t, err)
+		assert.Equal(t, types.PaulConfig{}, cfg)
+		assert.Equal(t, 3, len(m.requests))
+		cache.get(ctx, client, "Spazzy757", "paul", "main", time.Hour)
+		assert.Equal(t, 3, len(m.requests))
+	})
+	t.Run("Test Other Errors Are GitHub Errors", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+		client, _ := newGithubClient(config.Config{APIURL: server.URL + "/"}, server.Client())
+		cache := newConfigCache()
+		_, err := cache.get(ctx, client, "Spazzy757", "paul", "master", time.Hour)
+		assert.Equal(t, ErrorKindGithub, Classify(err))
+	})
+}
+
+func getMockPushEvent(ref string, modified ...string) *github.PushEvent {
+	return &github.PushEvent{
+		Ref: github.String(ref),
+		Repo: &github.PushEventRepository{
+			Name:          github.String("paul"),
+			Owner:         &github.User{Login: github.String("Spazzy757")},
+			DefaultBranch: github.String("master"),
+		},
+		Commits: []*github.HeadCommit{{Modified: modified}},
+	}
+}
+
+func TestPushHandler(t *testing.T) {
+	defer func(cache *configCache) { repoConfigs = cache }(repoConfigs)
+	var tests = []struct {
+		name        string
+		event       *github.PushEvent
+		invalidated bool
+	}{
+		{"Test Config Changes On The Default Branch Invalidate The Cache", getMockPushEvent("refs/heads/master", "README.md", "PAUL.yaml"), true},
+		{"Test Changes To Other Config Locations Invalidate The Cache", getMockPushEvent("refs/heads/master", ".github/PAUL.yaml"), true},
+		{"Test Other Changes Keep The Cache", getMockPushEvent("refs/heads/master", "README.md"), false},
+		{"Test Config Changes On Other Branches Keep The Cache", getMockPushEvent("refs/heads/feature", "PAUL.yaml"), false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repoConfigs = newConfigCache()
+			repoConfigs.entries[configCacheKey("Spazzy757", "paul", "master")] = &cachedConfig{}
+			assert.Nil(t, PushHandler(test.event))
+			_, cached := repoConfigs.entries[configCacheKey("Spazzy757", "paul", "master")]
+			assert.Equal(t, !test.invalidated, cached)
+		})
+	}
+}
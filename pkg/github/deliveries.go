@@ -0,0 +1,97 @@
+package github
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+const (
+	// deliveryLimit is the number of deliveries remembered by default
+	deliveryLimit = 10000
+	// deliveryTTL is how long deliveries are remembered by default
+	deliveryTTL = 24 * time.Hour
+)
+
+/*
+DeliveryStore remembers the X-GitHub-Delivery ids of accepted webhooks so
+redelivered webhooks are not processed twice, ids of webhooks that failed
+to be processed are forgotten
+*/
+type DeliveryStore interface {
+	// Seen records a delivery and reports whether it was already recorded
+	Seen(id string) (bool, error)
+	// Forget removes a delivery so it is accepted if it is sent again
+	Forget(id string) error
+}
+
+// deliveries is used by IncomingWebhook to skip redelivered webhooks
+var deliveries DeliveryStore = NewMemoryDeliveryStore(deliveryLimit, deliveryTTL)
+
+// UseDeliveryStore replaces the store used to skip redelivered webhooks
+func UseDeliveryStore(store DeliveryStore) {
+	deliveries = store
+}
+
+type delivery struct {
+	id      string
+	expires time.Time
+}
+
+/*
+MemoryDeliveryStore is a DeliveryStore that keeps deliveries in memory for
+a TTL, once the limit is reached the least recently seen is forgotten
+*/
+type MemoryDeliveryStore struct {
+	mu    sync.Mutex
+	limit int
+	ttl   time.Duration
+	now   func() time.Time
+	order *list.List
+	index map[string]*list.Element
+}
+
+// NewMemoryDeliveryStore creates a store remembering limit deliveries for ttl
+func NewMemoryDeliveryStore(limit int, ttl time.Duration) *MemoryDeliveryStore {
+	return &MemoryDeliveryStore{
+		limit: limit,
+		ttl:   ttl,
+		now:   time.Now,
+		order: list.New(),
+		index: map[string]*list.Element{},
+	}
+}
+
+// Seen records a delivery and reports whether it was already recorded
+func (s *MemoryDeliveryStore) Seen(id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if el, ok := s.index[id]; ok {
+		if now.Before(el.Value.(*delivery).expires) {
+			s.order.MoveToFront(el)
+			return true, nil
+		}
+		s.remove(el)
+	}
+	s.index[id] = s.order.PushFront(&delivery{id: id, expires: now.Add(s.ttl)})
+	for s.order.Len() > s.limit {
+		s.remove(s.order.Back())
+	}
+	return false, nil
+}
+
+// Forget removes a delivery so it is accepted if it is sent again
+func (s *MemoryDeliveryStore) Forget(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if el, ok := s.index[id]; ok {
+		s.remove(el)
+	}
+	return nil
+}
+
+func (s *MemoryDeliveryStore) remove(el *list.Element) {
+	s.order.Remove(el)
+	delete(s.index, el.Value.(*delivery).id)
+}
@@ -0,0 +1,50 @@
+package github
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryDeliveryStore(t *testing.T) {
+	t.Run("Test Deliveries Are Only Seen Once", func(t *testing.T) {
+		store := NewMemoryDeliveryStore(10, time.Hour)
+		seen, err := store.Seen("1")
+		assert.Nil(t, err)
+		assert.False(t, seen)
+		seen, err = store.Seen("1")
+		assert.Nil(t, err)
+		assert.True(t, seen)
+	})
+	t.Run("Test Deliveries Expire", func(t *testing.T) {
+		now := time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)
+		store := NewMemoryDeliveryStore(10, time.Hour)
+		store.now = func() time.Time { return now }
+		store.Seen("1")
+		now = now.Add(59 * time.Minute)
+		seen, _ := store.Seen("1")
+		assert.True(t, seen)
+		now = now.Add(time.Minute)
+		seen, _ = store.Seen("1")
+		assert.False(t, seen)
+	})
+	t.Run("Test Least Recently Seen Is Evicted", func(t *testing.T) {
+		store := NewMemoryDeliveryStore(2, time.Hour)
+		store.Seen("1")
+		store.Seen("2")
+		store.Seen("1")
+		store.Seen("3")
+		seen, _ := store.Seen("1")
+		assert.True(t, seen)
+		seen, _ = store.Seen("2")
+		assert.False(t, seen)
+	})
+	t.Run("Test Forgotten Deliveries Are Accepted Again", func(t *testing.T) {
+		store := NewMemoryDeliveryStore(10, time.Hour)
+		store.Seen("1")
+		assert.Nil(t, store.Forget("1"))
+		seen, _ := store.Seen("1")
+		assert.False(t, seen)
+	})
+}
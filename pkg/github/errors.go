@@ -0,0 +1,135 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/google/go-github/v32/github"
+)
+
+// ErrorKind classifies why handling a webhook failed
+type ErrorKind int
+
+const (
+	// ErrorKindInternal is a failure in Paul itself
+	ErrorKindInternal ErrorKind = iota
+	// ErrorKindValidation is a webhook that failed validation or parsing
+	ErrorKindValidation
+	// ErrorKindConfig is a PAUL.yaml that could not be loaded
+	ErrorKindConfig
+	// ErrorKindGithub is a failed call to the GitHub API
+	ErrorKindGithub
+	// ErrorKindUpstream is a failed call to another API such as the cat API
+	ErrorKindUpstream
+	// ErrorKindQueue is a webhook that could not be queued for processing
+	ErrorKindQueue
+)
+
+// String names the kind of error for logging
+func (k ErrorKind) String() string {
+	switch k {
+	case ErrorKindValidation:
+		return "validation error"
+	case ErrorKindConfig:
+		return "config error"
+	case ErrorKindGithub:
+		return "github api error"
+	case ErrorKindUpstream:
+		return "upstream error"
+	case ErrorKindQueue:
+		return "queue error"
+	}
+	return "internal error"
+}
+
+// Error is an error that happened while handling a webhook
+type Error struct {
+	Kind ErrorKind
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%v: %v", e.Kind, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func validationError(err error) error {
+	return &Error{Kind: ErrorKindValidation, Err: err}
+}
+
+func configError(err error) error {
+	return &Error{Kind: ErrorKindConfig, Err: err}
+}
+
+func githubError(err error) error {
+	return &Error{Kind: ErrorKindGithub, Err: err}
+}
+
+func upstreamError(err error) error {
+	return &Error{Kind: ErrorKindUpstream, Err: err}
+}
+
+func queueError(err error) error {
+	return &Error{Kind: ErrorKindQueue, Err: err}
+}
+
+/*
+Classify returns the kind of an error returned by IncomingWebhook. Errors
+that were not classified where they happened are recognised by their
+type, errors from the GitHub client are GitHub API errors
+*/
+func Classify(err error) ErrorKind {
+	var paulErr *Error
+	if errors.As(err, &paulErr) {
+		return paulErr.Kind
+	}
+	var errResp *github.ErrorResponse
+	var rateErr *github.RateLimitError
+	var abuseErr *github.AbuseRateLimitError
+	var urlErr *url.Error
+	switch {
+	case errors.As(err, &errResp), errors.As(err, &rateErr),
+		errors.As(err, &abuseErr), errors.As(err, &urlErr):
+		return ErrorKindGithub
+	}
+	return ErrorKindInternal
+}
+
+// StatusCode returns the HTTP status code to respond with for an error
+func StatusCode(err error) int {
+	switch Classify(err) {
+	case ErrorKindValidation:
+		return http.StatusBadRequest
+	case ErrorKindConfig:
+		return http.StatusUnprocessableEntity
+	case ErrorKindGithub:
+		return http.StatusBadGateway
+	case ErrorKindUpstream, ErrorKindQueue:
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
+/*
+Retryable reports whether processing a webhook again could succeed,
+invalid webhooks, broken PAUL.yaml files and requests the GitHub API
+refused fail the same way every time. Rate limited requests are retried
+*/
+func Retryable(err error) bool {
+	switch Classify(err) {
+	case ErrorKindValidation, ErrorKindConfig:
+		return false
+	}
+	var errResp *github.ErrorResponse
+	if errors.As(err, &errResp) && errResp.Response != nil {
+		status := errResp.Response.StatusCode
+		return status < 400 || status >= 500 || status == http.StatusTooManyRequests
+	}
+	return true
+}
@@ -0,0 +1,63 @@
+package github
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-github/v32/github"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusCode(t *testing.T) {
+	var tests = []struct {
+		name     string
+		err      error
+		kind     ErrorKind
+		expected int
+	}{
+		{"Test Validation Error", validationError(fmt.Errorf("bad signature")), ErrorKindValidation, http.StatusBadRequest},
+		{"Test Config Error", configError(fmt.Errorf("bad yaml")), ErrorKindConfig, http.StatusUnprocessableEntity},
+		{"Test Upstream Error", upstreamError(fmt.Errorf("timeout")), ErrorKindUpstream, http.StatusServiceUnavailable},
+		{"Test Queue Error", queueError(fmt.Errorf("full")), ErrorKindQueue, http.StatusServiceUnavailable},
+		{"Test Wrapped Error Keeps Its Kind", fmt.Errorf("/cat: %w", upstreamError(fmt.Errorf("timeout"))), ErrorKindUpstream, http.StatusServiceUnavailable},
+		{"Test GitHub Client Error", fmt.Errorf("/label: %w", notFoundError()), ErrorKindGithub, http.StatusBadGateway},
+		{"Test GitHub Rate Limit", &github.RateLimitError{}, ErrorKindGithub, http.StatusBadGateway},
+		{"Test GitHub Network Error", &url.Error{Op: "Get", URL: "https://api.github.com", Err: fmt.Errorf("eof")}, ErrorKindGithub, http.StatusBadGateway},
+		{"Test Unknown Error", fmt.Errorf("unknown"), ErrorKindInternal, http.StatusInternalServerError},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.kind, Classify(test.err))
+			assert.Equal(t, test.expected, StatusCode(test.err))
+		})
+	}
+}
+
+func githubErrorResponse(status int) error {
+	return &github.ErrorResponse{Response: &http.Response{StatusCode: status}}
+}
+
+func TestRetryable(t *testing.T) {
+	var tests = []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"Test Validation Errors Are Not Retried", validationError(fmt.Errorf("bad payload")), false},
+		{"Test Config Errors Are Not Retried", fmt.Errorf("/cat: %w", configError(fmt.Errorf("bad yaml"))), false},
+		{"Test Refused GitHub Requests Are Not Retried", fmt.Errorf("/label: %w", notFoundError()), false},
+		{"Test Unprocessable GitHub Requests Are Not Retried", githubErrorResponse(http.StatusUnprocessableEntity), false},
+		{"Test Rate Limited GitHub Requests Are Retried", githubErrorResponse(http.StatusTooManyRequests), true},
+		{"Test Rate Limit Errors Are Retried", &github.RateLimitError{Response: &http.Response{StatusCode: http.StatusForbidden}}, true},
+		{"Test GitHub Server Errors Are Retried", githubErrorResponse(http.StatusBadGateway), true},
+		{"Test Upstream Errors Are Retried", upstreamError(fmt.Errorf("timeout")), true},
+		{"Test Unknown Errors Are Retried", fmt.Errorf("unknown"), true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, Retryable(test.err))
+		})
+	}
+}
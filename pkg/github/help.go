@@ -0,0 +1,62 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Spazzy757/paul/pkg/types"
+)
+
+func init() {
+	commands.register(&Command{
+		Name:        "help",
+		Description: "Lists the commands enabled in this repository",
+		Permission:  PermissionAnyone,
+		Handler:     handleHelp,
+	})
+}
+
+// handleHelp is the handler for the /help command
+func handleHelp(req *commandRequest) error {
+	return createComment(req, helpText(commands, req.config))
+}
+
+/*
+helpText builds a Markdown table of the commands enabled by the repo
+config, it is generated from the registry so it can not drift from
+what Paul actually runs
+*/
+func helpText(r *registry, cfg *types.PaulConfig) string {
+	var help strings.Builder
+	help.WriteString("These are the commands enabled in this repository:\n\n")
+	help.WriteString("| Command | Arguments | Description | Who can run it |\n")
+	help.WriteString("| --- | --- | --- | --- |\n")
+	for _, cmd := range r.enabled(cfg) {
+		names := []string{fmt.Sprintf("`/%v`", cmd.Name)}
+		for _, alias := range cmd.Aliases {
+			names = append(names, fmt.Sprintf("`/%v`", alias))
+		}
+		args := ""
+		if cmd.Args != "" {
+			args = fmt.Sprintf("`%v`", cmd.Args)
+		}
+		fmt.Fprintf(
+			&help,
+			"| %v | %v | %v | %v |\n",
+			strings.Join(names, ", "),
+			args,
+			strings.ReplaceAll(cmd.Description, "|", "\\|"),
+			capitalise(cmd.Permission.String()),
+		)
+	}
+	help.WriteString("\nCommands have to be at the start of their own line.")
+	return help.String()
+}
+
+// capitalise upper cases the first letter of a sentence
+func capitalise(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
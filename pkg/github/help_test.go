@@ -0,0 +1,56 @@
+package github
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Spazzy757/paul/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHelpText(t *testing.T) {
+	t.Run("Test Only Enabled Commands Are Listed", func(t *testing.T) {
+		cfg := &types.PaulConfig{
+			PullRequests: types.PullRequests{CatsEnabled: true},
+		}
+		help := helpText(commands, cfg)
+		assert.Contains(t, help, "| `/cat` |  | Posts a picture of a cat | Anyone |")
+		assert.Contains(t, help, "| `/help` |")
+		assert.NotContains(t, help, "`/dog`")
+		assert.NotContains(t, help, "`/label`")
+	})
+	t.Run("Test Arguments Aliases And Permissions Are Listed", func(t *testing.T) {
+		r := newRegistry()
+		r.register(&Command{
+			Name:        "example",
+			Aliases:     []string{"ex"},
+			Args:        "[thing]",
+			Description: "Does a | thing",
+			Permission:  PermissionMaintainer,
+		})
+		help := helpText(r, &types.PaulConfig{})
+		assert.Contains(t, help, "| `/example`, `/ex` | `[thing]` | Does a \\| thing | Maintainers |")
+	})
+	t.Run("Test Every Registered Command Is Listed When Enabled", func(t *testing.T) {
+		cfg := &types.PaulConfig{Commands: map[string]bool{}}
+		for _, cmd := range commands.commands {
+			if cmd.ConfigKey != "" {
+				cfg.Commands[cmd.ConfigKey] = true
+			}
+		}
+		help := helpText(commands, cfg)
+		for _, cmd := range commands.commands {
+			assert.True(t, strings.Contains(help, "`/"+cmd.Name+"`"), cmd.Name)
+		}
+	})
+}
+
+func TestHandleHelp(t *testing.T) {
+	t.Run("Test Help Is Posted As A Comment", func(t *testing.T) {
+		mc := &mockIssueClient{}
+		req := getMockCommandRequest("cat-command", mc, &types.PaulConfig{})
+		assert.Nil(t, handleHelp(req))
+		assert.Len(t, mc.comments, 1)
+		assert.Contains(t, mc.comments[0], "| Command |")
+	})
+}
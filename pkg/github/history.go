@@ -0,0 +1,114 @@
+package github
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/google/go-github/v32/github"
+)
+
+// historyLimit is the number of comments the command history remembers
+const historyLimit = 1000
+
+// commandHistory remembers which commands already ran for a comment
+type commandHistory struct {
+	sync.Mutex
+	limit int
+	order []string
+	ran   map[string]map[string]bool
+}
+
+func newCommandHistory(limit int) *commandHistory {
+	return &commandHistory{limit: limit, ran: map[string]map[string]bool{}}
+}
+
+// history is used by the webhook handlers to deduplicate edited comments
+var history = newCommandHistory(historyLimit)
+
+// pendingCommand is a command that has not run for a comment yet
+type pendingCommand struct {
+	invocation
+	// key identifies the command within the comment, see invocationKeys
+	key string
+}
+
+/*
+pending returns the commands in body that have not run for the comment yet,
+comments are identified by a key made with commentKey. Commands are only
+marked as run by done so commands that failed are returned again when the
+webhook is retried. Commands in previous, the body before an edit, are
+treated as already run so they are not repeated even if Paul restarted
+since. Repeating a command in a comment counts as a new command
+*/
+func (h *commandHistory) pending(key, body, previous string) []pendingCommand {
+	h.Lock()
+	defer h.Unlock()
+	ran := h.commands(key)
+	for _, key := range invocationKeys(parseCommands(previous)) {
+		ran[key] = true
+	}
+	var pending []pendingCommand
+	bodyInvocations := parseCommands(body)
+	for i, key := range invocationKeys(bodyInvocations) {
+		if !ran[key] {
+			pending = append(pending, pendingCommand{bodyInvocations[i], key})
+		}
+	}
+	return pending
+}
+
+// done marks a command returned by pending as run for the comment
+func (h *commandHistory) done(key string, cmd pendingCommand) {
+	h.Lock()
+	defer h.Unlock()
+	h.commands(key)[cmd.key] = true
+}
+
+// commands returns the commands that ran for a comment, the history lock
+// has to be held
+func (h *commandHistory) commands(key string) map[string]bool {
+	ran, ok := h.ran[key]
+	if !ok {
+		ran = map[string]bool{}
+		h.remember(key, ran)
+	}
+	return ran
+}
+
+// remember stores the commands of a comment, forgetting the oldest comment
+// once the limit is reached
+func (h *commandHistory) remember(key string, ran map[string]bool) {
+	if len(h.order) >= h.limit {
+		delete(h.ran, h.order[0])
+		h.order = h.order[1:]
+	}
+	h.order = append(h.order, key)
+	h.ran[key] = ran
+}
+
+// commentKey identifies a comment, issue comments, reviews and review
+// comments have their own ids so the kind of comment is part of the key
+func commentKey(kind string, id int64) string {
+	return fmt.Sprintf("%v/%d", kind, id)
+}
+
+// invocationKeys identifies each command by its text and how many times
+// the same command appeared before it in the comment
+func invocationKeys(invocations []invocation) []string {
+	seen := map[string]int{}
+	keys := make([]string, len(invocations))
+	for i, inv := range invocations {
+		key := fmt.Sprintf("/%v %v", inv.name, inv.line)
+		keys[i] = fmt.Sprintf("%v#%d", key, seen[key])
+		seen[key]++
+	}
+	return keys
+}
+
+// previousBody returns the body of a comment before it was edited
+func previousBody(changes *github.EditChange) string {
+	if changes == nil || changes.Body == nil || changes.Body.From == nil {
+		return ""
+	}
+	return *changes.Body.From
+}
@@ -0,0 +1,81 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v32/github"
+	"github.com/stretchr/testify/assert"
+)
+
+func names(pending []pendingCommand) []string {
+	result := []string{}
+	for _, cmd := range pending {
+		result = append(result, cmd.name)
+	}
+	return result
+}
+
+// runAll marks every pending command of a comment as run
+func runAll(h *commandHistory, key, body, previous string) []pendingCommand {
+	pending := h.pending(key, body, previous)
+	for _, cmd := range pending {
+		h.done(key, cmd)
+	}
+	return pending
+}
+
+func TestCommandHistory(t *testing.T) {
+	t.Run("Test Created Comments Run Every Command", func(t *testing.T) {
+		h := newCommandHistory(10)
+		assert.Equal(t, []string{"cat", "cat", "dog"}, names(h.pending(commentKey("issue_comment", 1), "/cat\n/cat\n/dog", "")))
+	})
+	t.Run("Test Edits Only Run Added Commands", func(t *testing.T) {
+		h := newCommandHistory(10)
+		runAll(h, commentKey("issue_comment", 1), "/cat\n/label bug", "")
+		body := "/cat\n/label bug\n/dog\n/cat"
+		assert.Equal(t, []string{"dog", "cat"}, names(h.pending(commentKey("issue_comment", 1), body, "/cat\n/label bug")))
+	})
+	t.Run("Test Corrected Commands Run", func(t *testing.T) {
+		h := newCommandHistory(10)
+		runAll(h, commentKey("issue_comment", 1), "/label bgu", "")
+		pending := h.pending(commentKey("issue_comment", 1), "/label bug", "/label bgu")
+		assert.Equal(t, []string{"label"}, names(pending))
+		assert.Equal(t, []string{"bug"}, pending[0].args)
+	})
+	t.Run("Test Edits Do Not Rerun Commands That Already Ran", func(t *testing.T) {
+		h := newCommandHistory(10)
+		runAll(h, commentKey("issue_comment", 1), "/cat", "")
+		runAll(h, commentKey("issue_comment", 1), "/dog", "/cat")
+		assert.Empty(t, h.pending(commentKey("issue_comment", 1), "/cat\n/dog", "/dog"))
+	})
+	t.Run("Test Edits After A Restart Use The Previous Body", func(t *testing.T) {
+		h := newCommandHistory(10)
+		assert.Equal(t, []string{"dog"}, names(h.pending(commentKey("issue_comment", 1), "/cat\n/dog", "/cat")))
+	})
+	t.Run("Test Comment Kinds Are Kept Apart", func(t *testing.T) {
+		h := newCommandHistory(10)
+		runAll(h, commentKey("issue_comment", 1), "/cat", "")
+		assert.Equal(t, []string{"cat"}, names(h.pending(commentKey("pull_request_review", 1), "/cat", "")))
+	})
+	t.Run("Test Commands Are Pending Until Done", func(t *testing.T) {
+		h := newCommandHistory(10)
+		pending := h.pending(commentKey("issue_comment", 1), "/cat\n/dog", "")
+		h.done(commentKey("issue_comment", 1), pending[0])
+		assert.Equal(t, []string{"dog"}, names(h.pending(commentKey("issue_comment", 1), "/cat\n/dog", "")))
+	})
+	t.Run("Test Oldest Comments Are Forgotten", func(t *testing.T) {
+		h := newCommandHistory(2)
+		runAll(h, commentKey("issue_comment", 1), "/cat", "")
+		runAll(h, commentKey("issue_comment", 2), "/cat", "")
+		runAll(h, commentKey("issue_comment", 3), "/cat", "")
+		assert.Len(t, h.ran, 2)
+		assert.Equal(t, []string{"cat"}, names(h.pending(commentKey("issue_comment", 1), "/cat", "")))
+	})
+}
+
+func TestPreviousBody(t *testing.T) {
+	t.Run("Test No Changes", func(t *testing.T) {
+		assert.Equal(t, "", previousBody(nil))
+		assert.Equal(t, "", previousBody(&github.EditChange{}))
+	})
+}
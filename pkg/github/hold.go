@@ -0,0 +1,117 @@
+package github
+
+import (
+	"github.com/google/go-github/v32/github"
+)
+
+// holdContext is the commit status context used to block held pull requests
+const holdContext = "paul/hold"
+
+// holdActions are the pull request actions that can change the hold status
+var holdActions = map[string]bool{
+	"opened":      true,
+	"reopened":    true,
+	"synchronize": true,
+	"labeled":     true,
+	"unlabeled":   true,
+}
+
+func init() {
+	commands.register(&Command{
+		Name:        "hold",
+		Description: "Stops the pull request from being merged",
+		Permission:  PermissionCollaborator,
+		ConfigKey:   "hold",
+		Handler:     handleHold,
+	})
+	commands.register(&Command{
+		Name:        "unhold",
+		Description: "Allows a held pull request to be merged",
+		Permission:  PermissionCollaborator,
+		ConfigKey:   "hold",
+		Handler:     handleUnhold,
+	})
+}
+
+// handleHold is the handler for the /hold command
+func handleHold(req *commandRequest) error {
+	if !req.pullRequest {
+		return createComment(req, "`/hold` can only be used on pull requests")
+	}
+	label := req.config.PullRequests.HoldLabelName()
+	if _, err := ensureLabel(req, label, true); err != nil {
+		return err
+	}
+	_, _, err := req.issueClient.client.AddLabelsToIssue(
+		req.issueClient.ctx,
+		req.owner,
+		req.repo,
+		req.number,
+		[]string{label},
+	)
+	if err != nil {
+		return err
+	}
+	return updateHoldStatus(req, true)
+}
+
+// handleUnhold is the handler for the /unhold command
+func handleUnhold(req *commandRequest) error {
+	if !req.pullRequest {
+		return createComment(req, "`/unhold` can only be used on pull requests")
+	}
+	if err := removeLabel(req, req.config.PullRequests.HoldLabelName()); err != nil {
+		return err
+	}
+	return updateHoldStatus(req, false)
+}
+
+// updateHoldStatus sets the hold commit status on the head of the pull request
+func updateHoldStatus(req *commandRequest, held bool) error {
+	pr, _, err := req.pullRequestClient.client.Get(
+		req.pullRequestClient.ctx,
+		req.owner,
+		req.repo,
+		req.number,
+	)
+	if err != nil {
+		return err
+	}
+	return setHoldStatus(req, pr.GetHead().GetSHA(), held)
+}
+
+/*
+syncHoldStatus sets the hold commit status from the labels of the pull
+request, so new commits and labels changed by hand keep the status correct
+*/
+func syncHoldStatus(req *commandRequest, pr *github.PullRequest) error {
+	held := false
+	for _, label := range pr.Labels {
+		held = held || label.GetName() == req.config.PullRequests.HoldLabelName()
+	}
+	return setHoldStatus(req, pr.GetHead().GetSHA(), held)
+}
+
+/*
+setHoldStatus sets the hold commit status on a commit, a failing status
+lets branch protection block merging while the pull request is held
+*/
+func setHoldStatus(req *commandRequest, sha string, held bool) error {
+	status := &github.RepoStatus{
+		State:       github.String("success"),
+		Description: github.String("Not on hold"),
+		Context:     github.String(holdContext),
+	}
+	if held {
+		status.State = github.String("failure")
+		status.Description = github.String("On hold, run /unhold to allow merging")
+	}
+	_, _, err := req.repoClient.client.CreateStatus(
+		req.repoClient.ctx,
+		req.owner,
+		req.repo,
+		sha,
+		status,
+	)
+	return err
+}
@@ -0,0 +1,54 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v32/github"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleHold(t *testing.T) {
+	t.Run("Test Hold Adds Label And Failing Status", func(t *testing.T) {
+		req := getMockMergeRequest()
+		mc := req.issueClient.client.(*mockIssueClient)
+		rc := req.repoClient.client.(*mockRepositoryClient)
+		assert.Nil(t, handleHold(req))
+		assert.Equal(t, []string{"do-not-merge/hold"}, mc.labels)
+		assert.Len(t, rc.created, 1)
+		assert.Equal(t, "failure", rc.created[0].GetState())
+		assert.Equal(t, holdContext, rc.created[0].GetContext())
+	})
+	t.Run("Test Unhold Removes Label And Passes Status", func(t *testing.T) {
+		req := getMockMergeRequest()
+		mc := req.issueClient.client.(*mockIssueClient)
+		mc.labels = []string{"do-not-merge/hold"}
+		rc := req.repoClient.client.(*mockRepositoryClient)
+		assert.Nil(t, handleUnhold(req))
+		assert.Empty(t, mc.labels)
+		assert.Equal(t, "success", rc.created[0].GetState())
+	})
+	t.Run("Test Hold On Issues Is Refused", func(t *testing.T) {
+		req := getMockMergeRequest()
+		req.pullRequest = false
+		mc := req.issueClient.client.(*mockIssueClient)
+		assert.Nil(t, handleHold(req))
+		assert.Empty(t, mc.labels)
+		assert.Len(t, mc.comments, 1)
+	})
+}
+
+func TestSyncHoldStatus(t *testing.T) {
+	t.Run("Test Status Follows The Hold Label", func(t *testing.T) {
+		req := getMockMergeRequest()
+		rc := req.repoClient.client.(*mockRepositoryClient)
+		pr := &github.PullRequest{
+			Head:   &github.PullRequestBranch{SHA: github.String("def456")},
+			Labels: []*github.Label{{Name: github.String("do-not-merge/hold")}},
+		}
+		assert.Nil(t, syncHoldStatus(req, pr))
+		pr.Labels = nil
+		assert.Nil(t, syncHoldStatus(req, pr))
+		assert.Equal(t, "failure", rc.created[0].GetState())
+		assert.Equal(t, "success", rc.created[1].GetState())
+	})
+}
@@ -2,11 +2,8 @@ package github
 
 import (
 	"context"
-	"fmt"
-	"github.com/Spazzy757/paul/pkg/animals"
+
 	"github.com/google/go-github/v32/github"
-	"log"
-	"strings"
 )
 
 // interface to make testing logic easier
@@ -18,6 +15,66 @@ type issue interface {
 		number int,
 		comment *github.IssueComment,
 	) (*github.IssueComment, *github.Response, error)
+	AddLabelsToIssue(
+		ctx context.Context,
+		owner string,
+		repo string,
+		number int,
+		labels []string,
+	) ([]*github.Label, *github.Response, error)
+	RemoveLabelForIssue(
+		ctx context.Context,
+		owner string,
+		repo string,
+		number int,
+		label string,
+	) (*github.Response, error)
+	GetLabel(
+		ctx context.Context,
+		owner string,
+		repo string,
+		name string,
+	) (*github.Label, *github.Response, error)
+	CreateLabel(
+		ctx context.Context,
+		owner string,
+		repo string,
+		label *github.Label,
+	) (*github.Label, *github.Response, error)
+	AddAssignees(
+		ctx context.Context,
+		owner string,
+		repo string,
+		number int,
+		assignees []string,
+	) (*github.Issue, *github.Response, error)
+	RemoveAssignees(
+		ctx context.Context,
+		owner string,
+		repo string,
+		number int,
+		assignees []string,
+	) (*github.Issue, *github.Response, error)
+	Edit(
+		ctx context.Context,
+		owner string,
+		repo string,
+		number int,
+		issue *github.IssueRequest,
+	) (*github.Issue, *github.Response, error)
+	Lock(
+		ctx context.Context,
+		owner string,
+		repo string,
+		number int,
+		opts *github.LockIssueOptions,
+	) (*github.Response, error)
+	Unlock(
+		ctx context.Context,
+		owner string,
+		repo string,
+		number int,
+	) (*github.Response, error)
 }
 
 // struct to make testing logic easier
@@ -30,113 +87,39 @@ type issueClient struct {
 IssueCommentHandler takes an incoming event of type IssueCommentEvent and
 runs logic against it
 */
-func IssueCommentHandler(event *github.IssueCommentEvent) {
-	// load github client
-	client, ctx := getClient(*event.Installation.ID)
-	// load Paul Config from repo
-	cfg, err := getPaulConfig(
-		event.Repo.Owner.Login,
-		event.Repo.Name,
-		client,
-		event.Repo.GetContentsURL(),
-		ctx,
-	)
-	if err != nil {
-		log.Fatalf("An error occurred fetching config %v", err)
+func IssueCommentHandler(event *github.IssueCommentEvent) error {
+	// Check comments for any commands, edited comments only run
+	// the commands that were added by the edit
+	action := event.GetAction()
+	if action != "created" && action != "edited" {
+		return nil
 	}
-
-	// Check comments for any commands
-	if *event.Action == "created" {
-		// Get Comment
-		comment := event.GetComment()
-		// Get Which Command is run
-		// Throw away args as they are not used currently
-		cmd, _ := getCommand(*comment.Body)
-		// Create Client To pass through to handlers
-		isClient := &issueClient{
-			ctx:    ctx,
-			client: client.Issues,
-		}
-		// Switch statement to handle different commands
-		var err error
-		switch {
-		// Case of /cat command
-		case cmd == "cat" && cfg.PullRequests.CatsEnabled:
-			// Get the Cat Client
-			animalClient := animals.NewCatClient()
-			err = handleCats(event, isClient, animalClient)
-		// Case of /dog command
-		case cmd == "dog" && cfg.PullRequests.DogsEnabled:
-			// Get the Dog Client
-			animalClient := animals.NewDogClient()
-			err = handleDogs(event, isClient, animalClient)
-		default:
-			break
-		}
-		if err != nil {
-			log.Fatalf("An error occurred with the command %v: %v", cmd, err)
-		}
-	}
-}
-
-// getCommand strips out the command and any args that are given
-func getCommand(comment string) (string, []string) {
-	var args []string
-	if !strings.HasPrefix(comment, "/") {
-		return "", args
-	}
-	commands := strings.Split(comment[1:], " ")
-	return commands[0], commands[1:]
-}
-
-// handleCats is the handler for the /cat command
-func handleCats(
-	is *github.IssueCommentEvent,
-	isClient *issueClient,
-	catClient *animals.Client,
-) error {
-	cat, err := catClient.GetCat()
+	// load github client and Paul Config from repo
+	client, ctx, cfg, err := getRepoClient(event.Installation.GetID(), event.Repo)
 	if err != nil {
 		return err
 	}
-	message := fmt.Sprintf("My Most Trusted Minion\n\n ![my favorite minion](%v)", cat.Url)
-	catErr := createIssueComment(is, isClient, message)
-	if catErr != nil {
-		return catErr
-	}
-	return nil
-}
-
-// handleDogs is the handler for the /dog command
-func handleDogs(
-	is *github.IssueCommentEvent,
-	isClient *issueClient,
-	dogClient *animals.Client,
-) error {
-	dog, err := dogClient.GetDog()
-	if err != nil {
-		return err
-	}
-	message := fmt.Sprintf("Despite how it looks it is well trained\n\n ![loyal soldier](%v)", dog.Url)
-	dogErr := createIssueComment(is, isClient, message)
-	if dogErr != nil {
-		return dogErr
-	}
-	return nil
+	// Get Comment
+	comment := event.GetComment()
+	// Create Clients To pass through to handlers
+	clients := newCommandClients(client, ctx)
+	req := newCommandRequest(event, clients, &cfg, invocation{})
+	return runComment(
+		req,
+		commentKey("issue_comment", comment.GetID()),
+		comment.GetBody(),
+		previousBody(event.GetChanges()),
+	)
 }
 
-// createIssueComment sends a comment to an issue/pull request
-func createIssueComment(
-	is *github.IssueCommentEvent,
-	client *issueClient,
-	message string,
-) error {
+// createComment sends a comment to the issue/pull request of the request
+func createComment(req *commandRequest, message string) error {
 	comment := &github.IssueComment{Body: &message}
-	_, _, err := client.client.CreateComment(
-		client.ctx,
-		*is.Repo.Owner.Login,
-		is.Repo.GetName(),
-		is.Issue.GetNumber(),
+	_, _, err := req.issueClient.client.CreateComment(
+		req.issueClient.ctx,
+		req.owner,
+		req.repo,
+		req.number,
 		comment,
 	)
 	if err != nil {
@@ -4,10 +4,8 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"github.com/Spazzy757/paul/pkg/animals"
-	"github.com/Spazzy757/paul/pkg/helpers"
+	"github.com/Spazzy757/paul/pkg/types"
 	"github.com/google/go-github/v32/github"
-	"github.com/stretchr/testify/assert"
 	"io/ioutil"
 	"net/http"
 	"testing"
@@ -20,149 +18,176 @@ func getIssueCommentMockPayload(payloadType string) []byte {
 }
 
 type mockIssueClient struct {
-	resp *github.IssueComment
+	resp          *github.IssueComment
+	comments      []string
+	repoLabels    []string
+	createdLabels []*github.Label
+	labels        []string
+	assignees     []string
+	state         string
+	title         string
+	locked        bool
+	lockReason    string
+	commentErr    error
+}
+
+func notFoundError() error {
+	return &github.ErrorResponse{
+		Response: &http.Response{StatusCode: http.StatusNotFound},
+	}
 }
 
 func (m *mockIssueClient) CreateComment(
 	ctx context.Context,
 	owner, repo string,
 	number int,
-	review *github.IssueComment,
+	comment *github.IssueComment,
 ) (*github.IssueComment, *github.Response, error) {
+	if m.commentErr != nil {
+		return nil, nil, m.commentErr
+	}
+	m.comments = append(m.comments, comment.GetBody())
 	return m.resp, nil, nil
 }
 
-func TestCreateComment(t *testing.T) {
-	t.Run("Test Issue Comment Webhook is Handled correctly", func(t *testing.T) {
-		webhookPayload := getIssueCommentMockPayload("pr")
+// getMockCommandRequest builds a commandRequest from one of the mock payloads
+func getMockCommandRequest(payloadType string, client issue, cfg *types.PaulConfig) *commandRequest {
+	webhookPayload := getIssueCommentMockPayload(payloadType)
+	req, _ := http.NewRequest("POST", "/", bytes.NewBuffer(webhookPayload))
+	req.Header.Set("X-GitHub-Event", "issue_comment")
+	event, _ := github.ParseWebHook(github.WebHookType(req), webhookPayload)
+	clients := commandClients{
+		issueClient:       &issueClient{ctx: context.Background(), client: client},
+		repoClient:        &repositoryClient{ctx: context.Background(), client: &mockRepositoryClient{}},
+		pullRequestClient: &pullRequestClient{ctx: context.Background(), client: &mockClient{}},
+		checksClient:      &checksClient{ctx: context.Background(), client: &mockChecksClient{}},
+		reactionClient:    &reactionClient{ctx: context.Background(), client: &mockReactionClient{}},
+	}
+	return newCommandRequest(event.(*github.IssueCommentEvent), clients, cfg, invocation{args: []string{}})
+}
 
-		req, _ := http.NewRequest("POST", "/", bytes.NewBuffer(webhookPayload))
-		req.Header.Set("X-GitHub-Event", "issue_comment")
-		ctx := context.Background()
-		mc := &mockIssueClient{
-			resp: &github.IssueComment{
-				ID: github.Int64(1),
-			},
+func (m *mockIssueClient) AddLabelsToIssue(
+	ctx context.Context,
+	owner, repo string,
+	number int,
+	labels []string,
+) ([]*github.Label, *github.Response, error) {
+	m.labels = append(m.labels, labels...)
+	return nil, nil, nil
+}
+
+func (m *mockIssueClient) RemoveLabelForIssue(
+	ctx context.Context,
+	owner, repo string,
+	number int,
+	label string,
+) (*github.Response, error) {
+	for i, applied := range m.labels {
+		if applied == label {
+			m.labels = append(m.labels[:i], m.labels[i+1:]...)
+			return nil, nil
 		}
-		pr := &issueClient{ctx: ctx, client: mc}
-		event, _ := github.ParseWebHook(github.WebHookType(req), webhookPayload)
-		switch e := event.(type) {
-		case *github.IssueCommentEvent:
-			if err := createIssueComment(e, pr, "test"); err != nil {
-				t.Fatalf("comment on issue: %v", err)
-			}
-		default:
-			t.Fatalf("Event Type Not Issue Comment")
+	}
+	return nil, notFoundError()
+}
+
+func (m *mockIssueClient) GetLabel(
+	ctx context.Context,
+	owner, repo, name string,
+) (*github.Label, *github.Response, error) {
+	for _, label := range m.repoLabels {
+		if label == name {
+			return &github.Label{Name: github.String(name)}, nil, nil
 		}
-	})
+	}
+	return nil, nil, notFoundError()
 }
 
-func TestGetCommand(t *testing.T) {
-	t.Run("Test If Command, command returned", func(t *testing.T) {
-		comment := "/cat"
-		expected := "cat"
-		cmd, _ := getCommand(comment)
-		assert.Equal(t, expected, cmd)
-	})
-	t.Run("Test If not Command, nothing is returned", func(t *testing.T) {
-		comment := "cat"
-		expected := ""
-		cmd, _ := getCommand(comment)
-		assert.Equal(t, expected, cmd)
-	})
-	t.Run("Test If Command has args, command and args returned", func(t *testing.T) {
-		comment := "/label invalid"
-		expectedCommand := "label"
-		expectedArgs := []string{"invalid"}
-		cmd, args := getCommand(comment)
-		assert.Equal(t, expectedCommand, cmd)
-		assert.Equal(t, expectedArgs, args)
-	})
+func (m *mockIssueClient) CreateLabel(
+	ctx context.Context,
+	owner, repo string,
+	label *github.Label,
+) (*github.Label, *github.Response, error) {
+	m.createdLabels = append(m.createdLabels, label)
+	m.repoLabels = append(m.repoLabels, label.GetName())
+	return label, nil, nil
+}
 
+func (m *mockIssueClient) AddAssignees(
+	ctx context.Context,
+	owner, repo string,
+	number int,
+	assignees []string,
+) (*github.Issue, *github.Response, error) {
+	m.assignees = append(m.assignees, assignees...)
+	return nil, nil, nil
 }
 
-func TestHandleCats(t *testing.T) {
-	t.Run("Test Issue Comment Webhook is Handled correctly", func(t *testing.T) {
-		webhookPayload := getIssueCommentMockPayload("cat-command")
-		catAPIResponse := `[
-            {
-                "breeds":[],
-                "id":"40g",
-                "url":"https://cdn2.thecatapi.com/images/40g.jpg",
-                "width":640,
-                "height":426
-            }
-        ]`
-		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			w.Write([]byte(catAPIResponse))
-		})
-		httpClient, teardown := helpers.MockHTTPClient(h)
-		defer teardown()
-
-		catClient := animals.NewCatClient()
-		catClient.HttpClient = httpClient
-		catClient.Url = "https://example.com"
-
-		req, _ := http.NewRequest("POST", "/", bytes.NewBuffer(webhookPayload))
-		req.Header.Set("X-GitHub-Event", "issue_comment")
-		ctx := context.Background()
-		mc := &mockIssueClient{
-			resp: &github.IssueComment{
-				ID: github.Int64(1),
-			},
+func (m *mockIssueClient) RemoveAssignees(
+	ctx context.Context,
+	owner, repo string,
+	number int,
+	assignees []string,
+) (*github.Issue, *github.Response, error) {
+	var remaining []string
+	for _, assignee := range m.assignees {
+		removed := false
+		for _, user := range assignees {
+			removed = removed || assignee == user
 		}
-		is := &issueClient{ctx: ctx, client: mc}
-		event, _ := github.ParseWebHook(github.WebHookType(req), webhookPayload)
-		switch e := event.(type) {
-		case *github.IssueCommentEvent:
-			if err := handleCats(e, is, catClient); err != nil {
-				t.Fatalf("comment on issue: %v", err)
-			}
-		default:
-			t.Fatalf("Event Type Not Issue Comment")
+		if !removed {
+			remaining = append(remaining, assignee)
 		}
-	})
+	}
+	m.assignees = remaining
+	return nil, nil, nil
 }
 
-func TestHandleDogs(t *testing.T) {
+func (m *mockIssueClient) Edit(
+	ctx context.Context,
+	owner, repo string,
+	number int,
+	issue *github.IssueRequest,
+) (*github.Issue, *github.Response, error) {
+	if issue.State != nil {
+		m.state = issue.GetState()
+	}
+	if issue.Title != nil {
+		m.title = issue.GetTitle()
+	}
+	return nil, nil, nil
+}
+
+func (m *mockIssueClient) Lock(
+	ctx context.Context,
+	owner, repo string,
+	number int,
+	opts *github.LockIssueOptions,
+) (*github.Response, error) {
+	m.locked = true
+	m.lockReason = opts.LockReason
+	return nil, nil
+}
+
+func (m *mockIssueClient) Unlock(
+	ctx context.Context,
+	owner, repo string,
+	number int,
+) (*github.Response, error) {
+	m.locked = false
+	return nil, nil
+}
+
+func TestCreateComment(t *testing.T) {
 	t.Run("Test Issue Comment Webhook is Handled correctly", func(t *testing.T) {
-		webhookPayload := getIssueCommentMockPayload("dog-command")
-		dogAPIResponse := `[
-            {
-                "breeds":[],
-                "id":"40g",
-                "url":"https://cdn2.thedogapi.com/images/40g.jpg",
-                "width":640,
-                "height":426
-            }
-        ]`
-		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			w.Write([]byte(dogAPIResponse))
-		})
-		httpClient, teardown := helpers.MockHTTPClient(h)
-		defer teardown()
-
-		dogClient := animals.NewDogClient()
-		dogClient.HttpClient = httpClient
-		dogClient.Url = "https://example.com"
-
-		req, _ := http.NewRequest("POST", "/", bytes.NewBuffer(webhookPayload))
-		req.Header.Set("X-GitHub-Event", "issue_comment")
-		ctx := context.Background()
 		mc := &mockIssueClient{
 			resp: &github.IssueComment{
 				ID: github.Int64(1),
 			},
 		}
-		is := &issueClient{ctx: ctx, client: mc}
-		event, _ := github.ParseWebHook(github.WebHookType(req), webhookPayload)
-		switch e := event.(type) {
-		case *github.IssueCommentEvent:
-			if err := handleCats(e, is, dogClient); err != nil {
-				t.Fatalf("comment on issue: %v", err)
-			}
-		default:
-			t.Fatalf("Event Type Not Issue Comment")
+		req := getMockCommandRequest("cat-command", mc, &types.PaulConfig{})
+		if err := createComment(req, "test"); err != nil {
+			t.Fatalf("comment on issue: %v", err)
 		}
 	})
 }
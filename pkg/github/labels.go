@@ -0,0 +1,177 @@
+package github
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v32/github"
+)
+
+func init() {
+	commands.register(&Command{
+		Name:        "label",
+		Args:        "label [label ...]",
+		Description: "Adds labels from the allowed list",
+		Permission:  PermissionAnyone,
+		ConfigKey:   "label",
+		Handler:     handleLabel,
+	})
+	commands.register(&Command{
+		Name:        "remove-label",
+		Args:        "label [label ...]",
+		Description: "Removes labels from the allowed list",
+		Permission:  PermissionAnyone,
+		ConfigKey:   "label",
+		Handler:     handleRemoveLabel,
+	})
+}
+
+// handleLabel is the handler for the /label command
+func handleLabel(req *commandRequest) error {
+	allowed, denied := splitAllowedLabels(req)
+	var missing []string
+	var labels []string
+	for _, label := range allowed {
+		exists, err := ensureLabel(req, label, req.config.Labels.CreateMissing)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			missing = append(missing, label)
+			continue
+		}
+		labels = append(labels, label)
+	}
+	if len(labels) > 0 {
+		_, _, err := req.issueClient.client.AddLabelsToIssue(
+			req.issueClient.ctx,
+			req.owner,
+			req.repo,
+			req.number,
+			labels,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	if len(denied) > 0 || len(missing) > 0 {
+		return createComment(req, labelReply(req, denied, missing))
+	}
+	return nil
+}
+
+// handleRemoveLabel is the handler for the /remove-label command
+func handleRemoveLabel(req *commandRequest) error {
+	allowed, denied := splitAllowedLabels(req)
+	for _, label := range allowed {
+		if err := removeLabel(req, label); err != nil {
+			return err
+		}
+	}
+	if len(denied) > 0 {
+		return createComment(req, labelReply(req, denied, nil))
+	}
+	return nil
+}
+
+// splitAllowedLabels splits the args into allowed and denied labels
+func splitAllowedLabels(req *commandRequest) ([]string, []string) {
+	var allowed, denied []string
+	for _, label := range req.args {
+		if req.config.Labels.IsAllowed(label) {
+			allowed = append(allowed, label)
+		} else {
+			denied = append(denied, label)
+		}
+	}
+	return allowed, denied
+}
+
+// labelReply explains why some labels were not applied
+func labelReply(req *commandRequest, denied, missing []string) string {
+	var message strings.Builder
+	if len(denied) > 0 {
+		fmt.Fprintf(&message, "The following labels are not allowed: %v\n\n", codeList(denied))
+		if len(req.config.Labels.Allowed) == 0 {
+			message.WriteString("No labels are allowed in this repository")
+		} else {
+			fmt.Fprintf(&message, "Allowed labels are: %v", codeList(req.config.Labels.Allowed))
+		}
+	}
+	if len(missing) > 0 {
+		if message.Len() > 0 {
+			message.WriteString("\n\n")
+		}
+		fmt.Fprintf(&message, "The following labels do not exist in this repository: %v", codeList(missing))
+	}
+	return message.String()
+}
+
+// codeList formats values as a comma separated list of inline code
+func codeList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = fmt.Sprintf("`%v`", value)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+/*
+ensureLabel checks that a label exists in the repository and creates it
+with the configured colour if create is true. It reports if the label
+exists once it returns
+*/
+func ensureLabel(req *commandRequest, label string, create bool) (bool, error) {
+	_, _, err := req.issueClient.client.GetLabel(
+		req.issueClient.ctx,
+		req.owner,
+		req.repo,
+		label,
+	)
+	if err == nil {
+		return true, nil
+	}
+	if !isNotFound(err) {
+		return false, err
+	}
+	if !create {
+		return false, nil
+	}
+	_, _, err = req.issueClient.client.CreateLabel(
+		req.issueClient.ctx,
+		req.owner,
+		req.repo,
+		&github.Label{
+			Name:  github.String(label),
+			Color: github.String(req.config.Labels.Colour(label)),
+		},
+	)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// removeLabel removes a label, labels that are not applied are ignored
+func removeLabel(req *commandRequest, label string) error {
+	_, err := req.issueClient.client.RemoveLabelForIssue(
+		req.issueClient.ctx,
+		req.owner,
+		req.repo,
+		req.number,
+		label,
+	)
+	if err != nil && !isNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// isNotFound checks if an error from the GitHub API is a 404
+func isNotFound(err error) bool {
+	if errResp, ok := err.(*github.ErrorResponse); ok {
+		return errResp.Response != nil && errResp.Response.StatusCode == http.StatusNotFound
+	}
+	return false
+}
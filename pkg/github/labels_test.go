@@ -0,0 +1,77 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/Spazzy757/paul/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleLabel(t *testing.T) {
+	cfg := &types.PaulConfig{
+		Labels: types.Labels{
+			Allowed: []string{"bug", "area/*"},
+			Colours: map[string]string{"area/github": "00ff00"},
+		},
+	}
+	t.Run("Test Allowed Existing Labels Are Added", func(t *testing.T) {
+		mc := &mockIssueClient{repoLabels: []string{"bug"}}
+		req := getMockCommandRequest("cat-command", mc, cfg)
+		req.args = []string{"bug"}
+		assert.Nil(t, handleLabel(req))
+		assert.Equal(t, []string{"bug"}, mc.labels)
+		assert.Empty(t, mc.comments)
+	})
+	t.Run("Test Labels Not Allowed Get A Reply", func(t *testing.T) {
+		mc := &mockIssueClient{repoLabels: []string{"bug", "invalid"}}
+		req := getMockCommandRequest("cat-command", mc, cfg)
+		req.args = []string{"invalid", "bug"}
+		assert.Nil(t, handleLabel(req))
+		assert.Equal(t, []string{"bug"}, mc.labels)
+		assert.Len(t, mc.comments, 1)
+		assert.Contains(t, mc.comments[0], "`invalid`")
+		assert.Contains(t, mc.comments[0], "`bug`, `area/*`")
+	})
+	t.Run("Test Missing Labels Are Not Created By Default", func(t *testing.T) {
+		mc := &mockIssueClient{}
+		req := getMockCommandRequest("cat-command", mc, cfg)
+		req.args = []string{"area/github"}
+		assert.Nil(t, handleLabel(req))
+		assert.Empty(t, mc.labels)
+		assert.Empty(t, mc.createdLabels)
+		assert.Len(t, mc.comments, 1)
+		assert.Contains(t, mc.comments[0], "do not exist")
+	})
+	t.Run("Test Missing Labels Are Created When Permitted", func(t *testing.T) {
+		createCfg := *cfg
+		createCfg.Labels.CreateMissing = true
+		mc := &mockIssueClient{}
+		req := getMockCommandRequest("cat-command", mc, &createCfg)
+		req.args = []string{"area/github"}
+		assert.Nil(t, handleLabel(req))
+		assert.Equal(t, []string{"area/github"}, mc.labels)
+		assert.Len(t, mc.createdLabels, 1)
+		assert.Equal(t, "00ff00", mc.createdLabels[0].GetColor())
+	})
+}
+
+func TestHandleRemoveLabel(t *testing.T) {
+	cfg := &types.PaulConfig{
+		Labels: types.Labels{Allowed: []string{"bug"}},
+	}
+	t.Run("Test Allowed Labels Are Removed", func(t *testing.T) {
+		mc := &mockIssueClient{labels: []string{"bug", "invalid"}}
+		req := getMockCommandRequest("cat-command", mc, cfg)
+		req.args = []string{"bug", "invalid"}
+		assert.Nil(t, handleRemoveLabel(req))
+		assert.Equal(t, []string{"invalid"}, mc.labels)
+		assert.Len(t, mc.comments, 1)
+	})
+	t.Run("Test Removing A Label That Is Not Applied", func(t *testing.T) {
+		mc := &mockIssueClient{}
+		req := getMockCommandRequest("cat-command", mc, cfg)
+		req.args = []string{"bug"}
+		assert.Nil(t, handleRemoveLabel(req))
+		assert.Empty(t, mc.comments)
+	})
+}
@@ -0,0 +1,402 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/Spazzy757/paul/pkg/types"
+	"github.com/google/go-github/v32/github"
+)
+
+// interface to make testing logic easier
+type checks interface {
+	ListCheckRunsForRef(
+		ctx context.Context,
+		owner string,
+		repo string,
+		ref string,
+		opts *github.ListCheckRunsOptions,
+	) (*github.ListCheckRunsResults, *github.Response, error)
+}
+
+// struct to make testing logic easier
+type checksClient struct {
+	ctx    context.Context
+	client checks
+}
+
+// checkState is the combined outcome of a required check
+type checkState int
+
+const (
+	checkPending checkState = iota
+	checkPassed
+	checkFailed
+)
+
+// refusalStore remembers the last reason a pull request was not merged
+// so the same reason is not commented on every event
+type refusalStore struct {
+	sync.Mutex
+	reasons map[string]string
+}
+
+func newRefusalStore() *refusalStore {
+	return &refusalStore{reasons: map[string]string{}}
+}
+
+// refusals is used by the merge handlers, pull requests are forgotten
+// once they are merged or closed
+var refusals = newRefusalStore()
+
+// refused reports whether the pull request was already refused for the reason
+func (s *refusalStore) refused(key, reason string) bool {
+	s.Lock()
+	defer s.Unlock()
+	return s.reasons[key] == reason
+}
+
+// refuse remembers the reason the pull request was last refused for
+func (s *refusalStore) refuse(key, reason string) {
+	s.Lock()
+	defer s.Unlock()
+	s.reasons[key] = reason
+}
+
+// forget removes a pull request that was merged or closed
+func (s *refusalStore) forget(key string) {
+	s.Lock()
+	defer s.Unlock()
+	delete(s.reasons, key)
+}
+
+//CheckSuiteHandler handler for the check suite event
+func CheckSuiteHandler(event *github.CheckSuiteEvent) error {
+	if event.GetAction() != "completed" {
+		return nil
+	}
+	return mergeHandler(event.Installation.GetID(), event.Repo, func(req *commandRequest) ([]int, error) {
+		var numbers []int
+		for _, pr := range event.CheckSuite.PullRequests {
+			numbers = append(numbers, pr.GetNumber())
+		}
+		if len(numbers) > 0 {
+			return numbers, nil
+		}
+		// Pull requests from forks are not listed on the check suite
+		return pullRequestsForCommit(req, event.CheckSuite.GetHeadSHA())
+	})
+}
+
+//StatusHandler handler for the commit status event
+func StatusHandler(event *github.StatusEvent) error {
+	if event.GetState() == "pending" {
+		return nil
+	}
+	return mergeHandler(event.Installation.GetID(), event.Repo, func(req *commandRequest) ([]int, error) {
+		return pullRequestsForCommit(req, event.GetSHA())
+	})
+}
+
+/*
+mergeHandler loads the client and config for a repository and evaluates
+every pull request returned by numbers if merging is enabled. Every pull
+request is evaluated even if an earlier one fails, the first error is returned
+*/
+func mergeHandler(
+	installationID int64,
+	repo *github.Repository,
+	numbers func(req *commandRequest) ([]int, error),
+) error {
+	client, ctx, cfg, err := getRepoClient(installationID, repo)
+	if err != nil {
+		return err
+	}
+	if !cfg.Merge.Enabled {
+		return nil
+	}
+	req := &commandRequest{
+		commandClients: newCommandClients(client, ctx),
+		owner:          repo.Owner.GetLogin(),
+		repo:           repo.GetName(),
+		pullRequest:    true,
+		config:         &cfg,
+	}
+	prs, err := numbers(req)
+	if err != nil {
+		return err
+	}
+	var firstErr error
+	for _, number := range prs {
+		prReq := *req
+		prReq.number = number
+		if err := evaluateMerge(&prReq); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("merging %v/%v#%v: %w", req.owner, req.repo, number, err)
+		}
+	}
+	return firstErr
+}
+
+// pullRequestsForCommit finds the open pull requests with the commit as their head
+func pullRequestsForCommit(req *commandRequest, sha string) ([]int, error) {
+	prs, _, err := req.pullRequestClient.client.ListPullRequestsWithCommit(
+		req.pullRequestClient.ctx,
+		req.owner,
+		req.repo,
+		sha,
+		&github.PullRequestListOptions{State: "open"},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var numbers []int
+	for _, pr := range prs {
+		if pr.GetState() == "open" && pr.GetHead().GetSHA() == sha {
+			numbers = append(numbers, pr.GetNumber())
+		}
+	}
+	return numbers, nil
+}
+
+/*
+evaluateMerge merges the pull request when every gate in the merge config
+passes. Pull requests that are not ready yet, because they are missing
+labels, approval or checks are still running, are left alone. Pull
+requests that are ready but blocked get a comment with the reason, the
+reason is forgotten once it no longer applies so it is given again if
+the pull request is blocked again
+*/
+func evaluateMerge(req *commandRequest) error {
+	pr, _, err := req.pullRequestClient.client.Get(
+		req.pullRequestClient.ctx,
+		req.owner,
+		req.repo,
+		req.number,
+	)
+	if err != nil {
+		return err
+	}
+	if pr.GetState() != "open" || pr.GetMerged() {
+		clearRefusal(req)
+		return nil
+	}
+	ready, reason, err := mergeGates(req, pr)
+	if err != nil {
+		return err
+	}
+	if !ready {
+		clearRefusal(req)
+		return nil
+	}
+	if reason != "" {
+		return refuseMerge(req, reason)
+	}
+	_, _, err = req.pullRequestClient.client.Merge(
+		req.pullRequestClient.ctx,
+		req.owner,
+		req.repo,
+		req.number,
+		"",
+		&github.PullRequestOptions{
+			SHA:         pr.GetHead().GetSHA(),
+			MergeMethod: req.config.Merge.MergeMethod(),
+		},
+	)
+	if err != nil {
+		if errResp, ok := err.(*github.ErrorResponse); ok {
+			return refuseMerge(req, fmt.Sprintf("GitHub refused the merge: %v", errResp.Message))
+		}
+		return err
+	}
+	clearRefusal(req)
+	return nil
+}
+
+/*
+mergeGates checks the pull request against the merge config, it reports
+whether the pull request is ready to be merged and for ready pull
+requests that are blocked the reason they can not be merged
+*/
+func mergeGates(req *commandRequest, pr *github.PullRequest) (bool, string, error) {
+	if pr.GetDraft() {
+		return false, "", nil
+	}
+	merge := req.config.Merge
+	labels := map[string]bool{}
+	for _, label := range pr.Labels {
+		labels[label.GetName()] = true
+	}
+	for _, label := range merge.RequiredLabels {
+		if !labels[label] {
+			return false, "", nil
+		}
+	}
+	if merge.RequireApproval {
+		approved, err := isApproved(req)
+		if err != nil || !approved {
+			return false, "", err
+		}
+	}
+	var blocking []string
+	for _, label := range blockingLabels(req.config) {
+		if labels[label] {
+			blocking = append(blocking, label)
+		}
+	}
+	if len(blocking) > 0 {
+		return true, fmt.Sprintf("it has the blocking labels %v", codeList(blocking)), nil
+	}
+	states, err := checkStates(req, pr.GetHead().GetSHA())
+	if err != nil {
+		return false, "", err
+	}
+	var failed []string
+	for _, check := range merge.RequiredChecks {
+		switch states[check] {
+		case checkPending:
+			return false, "", nil
+		case checkFailed:
+			failed = append(failed, check)
+		}
+	}
+	if len(failed) > 0 {
+		return true, fmt.Sprintf("the required checks %v failed", codeList(failed)), nil
+	}
+	if pr.Mergeable != nil && !pr.GetMergeable() {
+		return true, "it has conflicts with the base branch", nil
+	}
+	return true, "", nil
+}
+
+// blockingLabels are the labels that stop a pull request from being merged,
+// the hold label always blocks while /hold is enabled
+func blockingLabels(cfg *types.PaulConfig) []string {
+	blocking := cfg.Merge.BlockingLabels
+	if !cfg.CommandEnabled("hold") {
+		return blocking
+	}
+	hold := cfg.PullRequests.HoldLabelName()
+	for _, label := range blocking {
+		if label == hold {
+			return blocking
+		}
+	}
+	return append(append([]string{}, blocking...), hold)
+}
+
+// isApproved checks the latest review of every reviewer, approved pull
+// requests have at least one approval and no requested changes
+func isApproved(req *commandRequest) (bool, error) {
+	reviews, _, err := req.pullRequestClient.client.ListReviews(
+		req.pullRequestClient.ctx,
+		req.owner,
+		req.repo,
+		req.number,
+		&github.ListOptions{PerPage: 100},
+	)
+	if err != nil {
+		return false, err
+	}
+	latest := map[string]string{}
+	for _, review := range reviews {
+		switch review.GetState() {
+		case "APPROVED", "CHANGES_REQUESTED", "DISMISSED":
+			latest[review.GetUser().GetLogin()] = review.GetState()
+		}
+	}
+	approved := false
+	for _, state := range latest {
+		switch state {
+		case "CHANGES_REQUESTED":
+			return false, nil
+		case "APPROVED":
+			approved = true
+		}
+	}
+	return approved, nil
+}
+
+// checkStates collects commit statuses and check runs by their name
+func checkStates(req *commandRequest, sha string) (map[string]checkState, error) {
+	states := map[string]checkState{}
+	combined, _, err := req.repoClient.client.GetCombinedStatus(
+		req.repoClient.ctx,
+		req.owner,
+		req.repo,
+		sha,
+		&github.ListOptions{PerPage: 100},
+	)
+	if err != nil {
+		return nil, err
+	}
+	for _, status := range combined.Statuses {
+		switch status.GetState() {
+		case "success":
+			states[status.GetContext()] = checkPassed
+		case "failure", "error":
+			states[status.GetContext()] = checkFailed
+		}
+	}
+	runs, _, err := req.checksClient.client.ListCheckRunsForRef(
+		req.checksClient.ctx,
+		req.owner,
+		req.repo,
+		sha,
+		&github.ListCheckRunsOptions{ListOptions: github.ListOptions{PerPage: 100}},
+	)
+	if err != nil {
+		return nil, err
+	}
+	for _, run := range runs.CheckRuns {
+		if run.GetStatus() != "completed" {
+			continue
+		}
+		switch run.GetConclusion() {
+		case "success", "neutral", "skipped":
+			states[run.GetName()] = checkPassed
+		default:
+			states[run.GetName()] = checkFailed
+		}
+	}
+	return states, nil
+}
+
+// refuseMerge comments why the pull request was not merged, unless
+// that reason was already given the last time it was evaluated
+func refuseMerge(req *commandRequest, reason string) error {
+	key := refusalKey(req)
+	if refusals.refused(key, reason) {
+		return nil
+	}
+	err := createComment(req, fmt.Sprintf("Not merging this pull request because %v", reason))
+	if err != nil {
+		return err
+	}
+	refusals.refuse(key, reason)
+	return nil
+}
+
+func clearRefusal(req *commandRequest) {
+	refusals.forget(refusalKey(req))
+}
+
+func refusalKey(req *commandRequest) string {
+	return strings.ToLower(fmt.Sprintf("%v/%v#%v", req.owner, req.repo, req.number))
+}
+
+// mergeActions are the pull request actions that can change if it can be merged
+var mergeActions = map[string]bool{
+	"opened":           true,
+	"reopened":         true,
+	"synchronize":      true,
+	"labeled":          true,
+	"unlabeled":        true,
+	"ready_for_review": true,
+}
+
+// shouldEvaluateMerge checks if a pull request event should trigger a merge
+func shouldEvaluateMerge(event *github.PullRequestEvent, cfg *types.PaulConfig) bool {
+	return cfg.Merge.Enabled && mergeActions[event.GetAction()]
+}
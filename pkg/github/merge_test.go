@@ -0,0 +1,201 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Spazzy757/paul/pkg/types"
+	"github.com/google/go-github/v32/github"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockChecksClient struct {
+	runs []*github.CheckRun
+}
+
+func (m *mockChecksClient) ListCheckRunsForRef(
+	ctx context.Context,
+	owner, repo, ref string,
+	opts *github.ListCheckRunsOptions,
+) (*github.ListCheckRunsResults, *github.Response, error) {
+	return &github.ListCheckRunsResults{CheckRuns: m.runs}, nil, nil
+}
+
+// getMockMergeRequest builds a request for an open pull request with the labels
+func getMockMergeRequest(labels ...string) *commandRequest {
+	pr := &github.PullRequest{
+		Number: github.Int(9),
+		State:  github.String("open"),
+		Head:   &github.PullRequestBranch{SHA: github.String("abc123")},
+	}
+	for _, label := range labels {
+		pr.Labels = append(pr.Labels, &github.Label{Name: github.String(label)})
+	}
+	req := getMockCommandRequest("cat-command", &mockIssueClient{}, &types.PaulConfig{
+		Merge: types.Merge{
+			Enabled:         true,
+			Method:          "squash",
+			RequireApproval: true,
+			RequiredLabels:  []string{"lgtm"},
+			BlockingLabels:  []string{"do-not-merge/hold"},
+			RequiredChecks:  []string{"test", "ci/build"},
+		},
+	})
+	req.pullRequestClient.client = &mockClient{
+		pullRequest: pr,
+		listReviews: []*github.PullRequestReview{
+			{User: &github.User{Login: github.String("reviewer")}, State: github.String("APPROVED")},
+		},
+	}
+	req.repoClient.client = &mockRepositoryClient{
+		statuses: []*github.RepoStatus{
+			{Context: github.String("ci/build"), State: github.String("success")},
+		},
+	}
+	req.checksClient.client = &mockChecksClient{
+		runs: []*github.CheckRun{
+			{Name: github.String("test"), Status: github.String("completed"), Conclusion: github.String("success")},
+		},
+	}
+	return req
+}
+
+func TestEvaluateMerge(t *testing.T) {
+	defer func(store *refusalStore) { refusals = store }(refusals)
+	refusals = newRefusalStore()
+	t.Run("Test Merges When All Gates Pass", func(t *testing.T) {
+		req := getMockMergeRequest("lgtm")
+		pc := req.pullRequestClient.client.(*mockClient)
+		assert.Nil(t, evaluateMerge(req))
+		assert.Len(t, pc.merged, 1)
+		assert.Equal(t, "squash", pc.merged[0].MergeMethod)
+		assert.Equal(t, "abc123", pc.merged[0].SHA)
+	})
+	t.Run("Test Missing Required Label Waits Silently", func(t *testing.T) {
+		req := getMockMergeRequest()
+		pc := req.pullRequestClient.client.(*mockClient)
+		mc := req.issueClient.client.(*mockIssueClient)
+		assert.Nil(t, evaluateMerge(req))
+		assert.Empty(t, pc.merged)
+		assert.Empty(t, mc.comments)
+	})
+	t.Run("Test Requested Changes Block Approval", func(t *testing.T) {
+		req := getMockMergeRequest("lgtm")
+		pc := req.pullRequestClient.client.(*mockClient)
+		pc.listReviews = append(pc.listReviews, &github.PullRequestReview{
+			User:  &github.User{Login: github.String("other")},
+			State: github.String("CHANGES_REQUESTED"),
+		})
+		assert.Nil(t, evaluateMerge(req))
+		assert.Empty(t, pc.merged)
+	})
+	t.Run("Test Pending Checks Wait Silently", func(t *testing.T) {
+		req := getMockMergeRequest("lgtm")
+		req.checksClient.client = &mockChecksClient{}
+		pc := req.pullRequestClient.client.(*mockClient)
+		mc := req.issueClient.client.(*mockIssueClient)
+		assert.Nil(t, evaluateMerge(req))
+		assert.Empty(t, pc.merged)
+		assert.Empty(t, mc.comments)
+	})
+	t.Run("Test Blocking Label Is Commented Once", func(t *testing.T) {
+		req := getMockMergeRequest("lgtm", "do-not-merge/hold")
+		req.number = 1001
+		pc := req.pullRequestClient.client.(*mockClient)
+		mc := req.issueClient.client.(*mockIssueClient)
+		assert.Nil(t, evaluateMerge(req))
+		assert.Nil(t, evaluateMerge(req))
+		assert.Empty(t, pc.merged)
+		assert.Len(t, mc.comments, 1)
+		assert.Contains(t, mc.comments[0], "`do-not-merge/hold`")
+	})
+	t.Run("Test Held Pull Requests Are Not Merged", func(t *testing.T) {
+		req := getMockMergeRequest("lgtm", "on-hold")
+		req.number = 1005
+		req.config.Merge.BlockingLabels = nil
+		req.config.PullRequests.HoldLabel = "on-hold"
+		req.config.Commands = map[string]bool{"hold": true}
+		pc := req.pullRequestClient.client.(*mockClient)
+		mc := req.issueClient.client.(*mockIssueClient)
+		assert.Nil(t, evaluateMerge(req))
+		assert.Empty(t, pc.merged)
+		assert.Len(t, mc.comments, 1)
+		assert.Contains(t, mc.comments[0], "`on-hold`")
+	})
+	t.Run("Test Refusals Are Commented Again When The Comment Failed", func(t *testing.T) {
+		req := getMockMergeRequest("lgtm", "do-not-merge/hold")
+		req.number = 1006
+		mc := req.issueClient.client.(*mockIssueClient)
+		mc.commentErr = githubErrorResponse(http.StatusBadGateway)
+		assert.NotNil(t, evaluateMerge(req))
+		mc.commentErr = nil
+		assert.Nil(t, evaluateMerge(req))
+		assert.Len(t, mc.comments, 1)
+	})
+	t.Run("Test Refusals Are Commented Again Once The Gate Cleared", func(t *testing.T) {
+		req := getMockMergeRequest("lgtm", "do-not-merge/hold")
+		req.number = 1007
+		pc := req.pullRequestClient.client.(*mockClient)
+		mc := req.issueClient.client.(*mockIssueClient)
+		held := pc.pullRequest.Labels
+		assert.Nil(t, evaluateMerge(req))
+		pc.pullRequest.Labels = nil
+		assert.Nil(t, evaluateMerge(req))
+		pc.pullRequest.Labels = held
+		assert.Nil(t, evaluateMerge(req))
+		assert.Len(t, mc.comments, 2)
+	})
+	t.Run("Test Failed Checks Are Commented", func(t *testing.T) {
+		req := getMockMergeRequest("lgtm")
+		req.number = 1002
+		req.repoClient.client = &mockRepositoryClient{
+			statuses: []*github.RepoStatus{
+				{Context: github.String("ci/build"), State: github.String("failure")},
+			},
+		}
+		mc := req.issueClient.client.(*mockIssueClient)
+		assert.Nil(t, evaluateMerge(req))
+		assert.Len(t, mc.comments, 1)
+		assert.Contains(t, mc.comments[0], "`ci/build` failed")
+	})
+	t.Run("Test Refused Merge Is Commented", func(t *testing.T) {
+		req := getMockMergeRequest("lgtm")
+		req.number = 1003
+		pc := req.pullRequestClient.client.(*mockClient)
+		pc.mergeErr = &github.ErrorResponse{
+			Response: &http.Response{StatusCode: http.StatusMethodNotAllowed},
+			Message:  "Pull Request is not mergeable",
+		}
+		mc := req.issueClient.client.(*mockIssueClient)
+		assert.Nil(t, evaluateMerge(req))
+		assert.Len(t, mc.comments, 1)
+		assert.Contains(t, mc.comments[0], "Pull Request is not mergeable")
+	})
+	t.Run("Test Closed Pull Requests Are Forgotten", func(t *testing.T) {
+		req := getMockMergeRequest("lgtm", "do-not-merge/hold")
+		req.number = 1004
+		pc := req.pullRequestClient.client.(*mockClient)
+		assert.Nil(t, evaluateMerge(req))
+		assert.Contains(t, refusals.reasons, refusalKey(req))
+		pc.pullRequest.State = github.String("closed")
+		assert.Nil(t, evaluateMerge(req))
+		assert.NotContains(t, refusals.reasons, refusalKey(req))
+	})
+}
+
+func TestPullRequestsForCommit(t *testing.T) {
+	t.Run("Test Only Open Pull Requests With The Head Commit", func(t *testing.T) {
+		req := getMockMergeRequest()
+		req.pullRequestClient.client = &mockClient{
+			pullRequests: []*github.PullRequest{
+				{Number: github.Int(1), State: github.String("open"), Head: &github.PullRequestBranch{SHA: github.String("abc123")}},
+				{Number: github.Int(2), State: github.String("closed"), Head: &github.PullRequestBranch{SHA: github.String("abc123")}},
+				{Number: github.Int(3), State: github.String("open"), Head: &github.PullRequestBranch{SHA: github.String("def456")}},
+			},
+		}
+		numbers, err := pullRequestsForCommit(req, "abc123")
+		assert.Nil(t, err)
+		assert.Equal(t, []int{1}, numbers)
+	})
+}
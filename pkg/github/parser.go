@@ -0,0 +1,120 @@
+package github
+
+import (
+	"strings"
+	"unicode"
+)
+
+// invocation is a single command found in a comment
+type invocation struct {
+	name string
+	args []string
+	// line is the rest of the line after the command, for commands that
+	// take free text rather than arguments
+	line string
+}
+
+/*
+parseCommands finds every slash command in a comment body. A command has
+to be at the start of its own line, lines inside code fences and quoted
+replies are ignored. Commands are returned in the order they appear
+*/
+func parseCommands(body string) []invocation {
+	var invocations []invocation
+	inFence := false
+	fence := ""
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if marker := fenceMarker(line); marker != "" {
+			switch {
+			case !inFence:
+				inFence, fence = true, marker
+			case strings.HasPrefix(line, fence):
+				inFence, fence = false, ""
+			}
+			continue
+		}
+		if inFence || strings.HasPrefix(line, ">") {
+			continue
+		}
+		if inv, ok := parseLine(line); ok {
+			invocations = append(invocations, inv)
+		}
+	}
+	return invocations
+}
+
+// fenceMarker returns the fence used if the line opens or closes a code block
+func fenceMarker(line string) string {
+	for _, marker := range []string{"```", "~~~"} {
+		if strings.HasPrefix(line, marker) {
+			return marker
+		}
+	}
+	return ""
+}
+
+// parseLine parses a single line into a command and its arguments
+func parseLine(line string) (invocation, bool) {
+	if !strings.HasPrefix(line, "/") {
+		return invocation{}, false
+	}
+	end := strings.IndexFunc(line, unicode.IsSpace)
+	if end == -1 {
+		end = len(line)
+	}
+	name := line[1:end]
+	if !validCommandName(name) {
+		return invocation{}, false
+	}
+	return invocation{
+		name: name,
+		args: splitArgs(line[end:]),
+		line: strings.TrimSpace(line[end:]),
+	}, true
+}
+
+// validCommandName stops paths like /usr/bin being treated as commands
+func validCommandName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// splitArgs splits on whitespace while keeping quoted arguments together
+func splitArgs(s string) []string {
+	args := []string{}
+	var current strings.Builder
+	inArg := false
+	var quote rune
+	for _, r := range s {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			current.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote = r
+			inArg = true
+		case unicode.IsSpace(r):
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args
+}
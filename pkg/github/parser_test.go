@@ -0,0 +1,92 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCommands(t *testing.T) {
+	var tests = []struct {
+		name     string
+		body     string
+		expected []invocation
+	}{
+		{
+			name:     "Test Single Command",
+			body:     "/cat",
+			expected: []invocation{{name: "cat", args: []string{}}},
+		},
+		{
+			name:     "Test Text Without A Slash Is Not A Command",
+			body:     "cat",
+			expected: nil,
+		},
+		{
+			name:     "Test Command With An Argument",
+			body:     "/label invalid",
+			expected: []invocation{{name: "label", args: []string{"invalid"}, line: "invalid"}},
+		},
+		{
+			name: "Test Multiple Commands On Separate Lines",
+			body: "/label bug\r\n/assign me",
+			expected: []invocation{
+				{name: "label", args: []string{"bug"}, line: "bug"},
+				{name: "assign", args: []string{"me"}, line: "me"},
+			},
+		},
+		{
+			name: "Test Command After Text And Quoted Reply",
+			body: "> /close\n\nThanks for this!\n  /lgtm",
+			expected: []invocation{
+				{name: "lgtm", args: []string{}},
+			},
+		},
+		{
+			name: "Test Commands In Code Fences Are Ignored",
+			body: "```\n/cat\n~~~\n/dog\n```\n/dog",
+			expected: []invocation{
+				{name: "dog", args: []string{}},
+			},
+		},
+		{
+			name: "Test Quoted Arguments",
+			body: `/label "good first issue" 'help wanted'  bug`,
+			expected: []invocation{
+				{
+					name: "label",
+					args: []string{"good first issue", "help wanted", "bug"},
+					line: `"good first issue" 'help wanted'  bug`,
+				},
+			},
+		},
+		{
+			name: "Test Empty Quoted Argument",
+			body: `/retitle ""`,
+			expected: []invocation{
+				{name: "retitle", args: []string{""}, line: `""`},
+			},
+		},
+		{
+			name: "Test Rest Of Line Is Kept",
+			body: "/retitle   Fix: don't split   this title  ",
+			expected: []invocation{
+				{
+					name: "retitle",
+					args: []string{"Fix:", "dont split   this title"},
+					line: "Fix: don't split   this title",
+				},
+			},
+		},
+		{
+			name:     "Test Commands Must Start The Line",
+			body:     "please run /cat\n/usr/bin/paul\n/",
+			expected: nil,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, parseCommands(test.body))
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v32/github"
+)
+
+const (
+	// PermissionAuthor allows the author of the issue/pull request,
+	// collaborators and maintainers to run the command
+	PermissionAuthor Permission = iota + 1
+	// PermissionCollaborator allows repo collaborators with write access
+	// and maintainers to run the command
+	PermissionCollaborator
+	// PermissionMaintainer allows only the maintainers listed in PAUL.yaml
+	// to run the command
+	PermissionMaintainer
+	// PermissionAuthorOrMaintainer allows the author of the issue/pull
+	// request and the maintainers listed in PAUL.yaml to run the command
+	PermissionAuthorOrMaintainer
+)
+
+// String describes the permission, used in replies and help text
+func (p Permission) String() string {
+	switch p {
+	case PermissionAnyone:
+		return "anyone"
+	case PermissionAuthor:
+		return "the author, collaborators and maintainers"
+	case PermissionCollaborator:
+		return "collaborators and maintainers"
+	case PermissionMaintainer:
+		return "maintainers"
+	case PermissionAuthorOrMaintainer:
+		return "the author and maintainers"
+	}
+	return "nobody"
+}
+
+// interface to make testing logic easier
+type repository interface {
+	GetPermissionLevel(
+		ctx context.Context,
+		owner string,
+		repo string,
+		user string,
+	) (*github.RepositoryPermissionLevel, *github.Response, error)
+	IsCollaborator(
+		ctx context.Context,
+		owner string,
+		repo string,
+		user string,
+	) (bool, *github.Response, error)
+	GetCombinedStatus(
+		ctx context.Context,
+		owner string,
+		repo string,
+		ref string,
+		opts *github.ListOptions,
+	) (*github.CombinedStatus, *github.Response, error)
+	CreateStatus(
+		ctx context.Context,
+		owner string,
+		repo string,
+		ref string,
+		status *github.RepoStatus,
+	) (*github.RepoStatus, *github.Response, error)
+}
+
+// struct to make testing logic easier
+type repositoryClient struct {
+	ctx    context.Context
+	client repository
+}
+
+// isMaintainer checks if the user is listed as a maintainer in PAUL.yaml
+func (req *commandRequest) isMaintainer(user string) bool {
+	for _, maintainer := range req.config.Maintainers {
+		if strings.EqualFold(maintainer, user) {
+			return true
+		}
+	}
+	return false
+}
+
+// isAuthor checks if the user opened the issue/pull request
+func (req *commandRequest) isAuthor(user string) bool {
+	return strings.EqualFold(req.issueAuthor, user)
+}
+
+// isCollaborator checks if the user has write access to the repo
+func (req *commandRequest) isCollaborator(user string) (bool, error) {
+	level, _, err := req.repoClient.client.GetPermissionLevel(
+		req.repoClient.ctx,
+		req.owner,
+		req.repo,
+		user,
+	)
+	if err != nil {
+		if isNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	switch level.GetPermission() {
+	case "admin", "write":
+		return true, nil
+	}
+	return false, nil
+}
+
+// allowed checks if the user running the command has the given permission
+func (req *commandRequest) allowed(permission Permission) (bool, error) {
+	if permission == PermissionAnyone || req.isMaintainer(req.user) {
+		return true, nil
+	}
+	switch permission {
+	case PermissionAuthor:
+		if req.isAuthor(req.user) {
+			return true, nil
+		}
+		return req.isCollaborator(req.user)
+	case PermissionCollaborator:
+		return req.isCollaborator(req.user)
+	case PermissionAuthorOrMaintainer:
+		return req.isAuthor(req.user), nil
+	}
+	return false, nil
+}
+
+// denied lets the user know they are not allowed to run the command
+func denied(req *commandRequest, cmd *Command) error {
+	message := fmt.Sprintf(
+		"Sorry @%v, only %v can run `/%v` here",
+		req.user,
+		cmd.Permission,
+		cmd.Name,
+	)
+	return createComment(req, message)
+}
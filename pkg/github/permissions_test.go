@@ -0,0 +1,124 @@
+package github
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Spazzy757/paul/pkg/types"
+	"github.com/google/go-github/v32/github"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockRepositoryClient struct {
+	permissions map[string]string
+	statuses    []*github.RepoStatus
+	created     []*github.RepoStatus
+}
+
+func (m *mockRepositoryClient) CreateStatus(
+	ctx context.Context,
+	owner, repo, ref string,
+	status *github.RepoStatus,
+) (*github.RepoStatus, *github.Response, error) {
+	m.created = append(m.created, status)
+	return status, nil, nil
+}
+
+func (m *mockRepositoryClient) GetCombinedStatus(
+	ctx context.Context,
+	owner, repo, ref string,
+	opts *github.ListOptions,
+) (*github.CombinedStatus, *github.Response, error) {
+	return &github.CombinedStatus{Statuses: m.statuses}, nil, nil
+}
+
+func (m *mockRepositoryClient) GetPermissionLevel(
+	ctx context.Context,
+	owner, repo, user string,
+) (*github.RepositoryPermissionLevel, *github.Response, error) {
+	permission, ok := m.permissions[user]
+	if !ok {
+		return nil, nil, notFoundError()
+	}
+	return &github.RepositoryPermissionLevel{Permission: github.String(permission)}, nil, nil
+}
+
+func (m *mockRepositoryClient) IsCollaborator(
+	ctx context.Context,
+	owner, repo, user string,
+) (bool, *github.Response, error) {
+	_, ok := m.permissions[user]
+	return ok, nil, nil
+}
+
+// getMockPermissionRequest builds a request run by user on an issue opened by author
+func getMockPermissionRequest(user, author string, permissions map[string]string) *commandRequest {
+	req := getMockCommandRequest("cat-command", &mockIssueClient{}, &types.PaulConfig{
+		Maintainers: []string{"Spazzy757"},
+	})
+	req.user = user
+	req.issueAuthor = author
+	req.repoClient.client = &mockRepositoryClient{permissions: permissions}
+	return req
+}
+
+func TestAllowed(t *testing.T) {
+	permissions := map[string]string{
+		"writer": "write",
+		"admin":  "admin",
+		"reader": "read",
+	}
+	var tests = []struct {
+		name       string
+		user       string
+		permission Permission
+		expected   bool
+	}{
+		{"Test Anyone Can Run", "stranger", PermissionAnyone, true},
+		{"Test Author Can Run Author Commands", "author", PermissionAuthor, true},
+		{"Test Writer Can Run Author Commands", "writer", PermissionAuthor, true},
+		{"Test Stranger Cannot Run Author Commands", "stranger", PermissionAuthor, false},
+		{"Test Admin Can Run Collaborator Commands", "admin", PermissionCollaborator, true},
+		{"Test Reader Cannot Run Collaborator Commands", "reader", PermissionCollaborator, false},
+		{"Test Author Cannot Run Collaborator Commands", "author", PermissionCollaborator, false},
+		{"Test Maintainer Can Run Maintainer Commands", "spazzy757", PermissionMaintainer, true},
+		{"Test Admin Cannot Run Maintainer Commands", "admin", PermissionMaintainer, false},
+		{"Test Author Can Run Author Or Maintainer Commands", "author", PermissionAuthorOrMaintainer, true},
+		{"Test Maintainer Can Run Author Or Maintainer Commands", "spazzy757", PermissionAuthorOrMaintainer, true},
+		{"Test Writer Cannot Run Author Or Maintainer Commands", "writer", PermissionAuthorOrMaintainer, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := getMockPermissionRequest(test.user, "author", permissions)
+			allowed, err := req.allowed(test.permission)
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, allowed)
+		})
+	}
+}
+
+func TestRunEnforcesPermissions(t *testing.T) {
+	ran := false
+	r := newRegistry()
+	r.register(&Command{
+		Name:       "restricted",
+		Permission: PermissionMaintainer,
+		Handler: func(req *commandRequest) error {
+			ran = true
+			return nil
+		},
+	})
+	t.Run("Test Unauthorized User Gets A Reply", func(t *testing.T) {
+		req := getMockPermissionRequest("stranger", "author", nil)
+		mc := req.issueClient.client.(*mockIssueClient)
+		assert.Nil(t, r.run("restricted", req))
+		assert.False(t, ran)
+		assert.Len(t, mc.comments, 1)
+		assert.Contains(t, mc.comments[0], "only maintainers can run `/restricted`")
+	})
+	t.Run("Test Authorized User Runs The Command", func(t *testing.T) {
+		req := getMockPermissionRequest("Spazzy757", "author", nil)
+		assert.Nil(t, r.run("restricted", req))
+		assert.True(t, ran)
+	})
+}
@@ -2,33 +2,55 @@ package github
 
 import (
 	"context"
-	"log"
 
 	"github.com/google/go-github/v32/github"
 )
 
 //PullRequestHandler handler for the pull request event
-func PullRequestHandler(event *github.PullRequestEvent) {
-	client, ctx := getClient(*event.Installation.ID)
-	cfg, err := getPaulConfig(
-		event.Repo.Owner.Login,
-		event.Repo.Name,
-		client,
-		event.Repo.GetContentsURL(),
-		ctx,
-	)
+func PullRequestHandler(event *github.PullRequestEvent) error {
+	client, ctx, cfg, err := getRepoClient(event.Installation.GetID(), event.Repo)
 	if err != nil {
-		log.Fatalf("An error occurred fetching config %v", err)
+		return err
 	}
 	if cfg.PullRequests.OpenMessage != "" && *event.Action == "opened" {
 		pr := &pullRequestClient{ctx: ctx, client: client.PullRequests}
-		_ = comment(event.GetPullRequest(), pr, cfg.PullRequests.OpenMessage)
+		if err := greet(event.GetPullRequest(), pr, cfg.PullRequests.OpenMessage); err != nil {
+			return err
+		}
+	}
+	req := &commandRequest{
+		commandClients: newCommandClients(client, ctx),
+		owner:          event.Repo.Owner.GetLogin(),
+		repo:           event.Repo.GetName(),
+		number:         event.GetNumber(),
+		pullRequest:    true,
+		config:         &cfg,
+	}
+	// New commits need a fresh review so the lgtm label is removed
+	if *event.Action == "synchronize" && cfg.CommandEnabled("lgtm") {
+		if err := removeLGTM(req); err != nil {
+			return err
+		}
+	}
+	// Closed pull requests will not be merged so their refusal is forgotten
+	if event.GetAction() == "closed" {
+		clearRefusal(req)
 	}
-	// Check comments for any commands
-	if *event.Action == "created" {
-		changes := event.GetChanges()
-		log.Printf("%+v", changes.Body)
+	// Keep the hold status on the latest commit in line with the hold label
+	if cfg.CommandEnabled("hold") && holdActions[event.GetAction()] {
+		if err := syncHoldStatus(req, event.GetPullRequest()); err != nil {
+			return err
+		}
 	}
+	if shouldEvaluateMerge(event, &cfg) {
+		return evaluateMerge(req)
+	}
+	return nil
+}
+
+// removeLGTM removes the lgtm label from the pull request of the request
+func removeLGTM(req *commandRequest) error {
+	return removeLabel(req, req.config.PullRequests.LGTMLabelName())
 }
 
 type pullRequest interface {
@@ -39,6 +61,34 @@ type pullRequest interface {
 		number int,
 		review *github.PullRequestReviewRequest,
 	) (*github.PullRequestReview, *github.Response, error)
+	Get(
+		ctx context.Context,
+		owner string,
+		repo string,
+		number int,
+	) (*github.PullRequest, *github.Response, error)
+	ListReviews(
+		ctx context.Context,
+		owner string,
+		repo string,
+		number int,
+		opts *github.ListOptions,
+	) ([]*github.PullRequestReview, *github.Response, error)
+	ListPullRequestsWithCommit(
+		ctx context.Context,
+		owner string,
+		repo string,
+		sha string,
+		opts *github.PullRequestListOptions,
+	) ([]*github.PullRequest, *github.Response, error)
+	Merge(
+		ctx context.Context,
+		owner string,
+		repo string,
+		number int,
+		commitMessage string,
+		options *github.PullRequestOptions,
+	) (*github.PullRequestMergeResult, *github.Response, error)
 }
 
 type pullRequestClient struct {
@@ -64,3 +114,27 @@ func comment(pr *github.PullRequest, client *pullRequestClient, message string)
 	}
 	return nil
 }
+
+/*
+greet comments the open message on a new pull request. Webhooks are
+retried when a later step fails so pull requests that already have the
+message are not greeted again
+*/
+func greet(pr *github.PullRequest, client *pullRequestClient, message string) error {
+	reviews, _, err := client.client.ListReviews(
+		client.ctx,
+		pr.Base.User.GetLogin(),
+		pr.Base.Repo.GetName(),
+		pr.GetNumber(),
+		&github.ListOptions{PerPage: 100},
+	)
+	if err != nil {
+		return err
+	}
+	for _, review := range reviews {
+		if review.GetBody() == message {
+			return nil
+		}
+	}
+	return comment(pr, client, message)
+}
@@ -3,7 +3,9 @@ package github
 import (
 	"bytes"
 	"context"
+	"github.com/Spazzy757/paul/pkg/types"
 	"github.com/google/go-github/v32/github"
+	"github.com/stretchr/testify/assert"
 	"io/ioutil"
 	"net/http"
 	"testing"
@@ -15,10 +17,37 @@ func getMockPayload() []byte {
 }
 
 type mockClient struct {
-	resp *github.PullRequestReview
+	resp         *github.PullRequestReview
+	reviews      []*github.PullRequestReviewRequest
+	pullRequest  *github.PullRequest
+	listReviews  []*github.PullRequestReview
+	pullRequests []*github.PullRequest
+	merged       []*github.PullRequestOptions
+	mergeErr     error
+}
+
+func (m *mockClient) Get(ctx context.Context, owner string, repo string, number int) (*github.PullRequest, *github.Response, error) {
+	return m.pullRequest, nil, nil
+}
+
+func (m *mockClient) ListReviews(ctx context.Context, owner string, repo string, number int, opts *github.ListOptions) ([]*github.PullRequestReview, *github.Response, error) {
+	return m.listReviews, nil, nil
+}
+
+func (m *mockClient) ListPullRequestsWithCommit(ctx context.Context, owner string, repo string, sha string, opts *github.PullRequestListOptions) ([]*github.PullRequest, *github.Response, error) {
+	return m.pullRequests, nil, nil
+}
+
+func (m *mockClient) Merge(ctx context.Context, owner string, repo string, number int, commitMessage string, options *github.PullRequestOptions) (*github.PullRequestMergeResult, *github.Response, error) {
+	if m.mergeErr != nil {
+		return nil, nil, m.mergeErr
+	}
+	m.merged = append(m.merged, options)
+	return &github.PullRequestMergeResult{Merged: github.Bool(true)}, nil, nil
 }
 
 func (m *mockClient) CreateReview(ctx context.Context, owner string, repo string, number int, review *github.PullRequestReviewRequest) (*github.PullRequestReview, *github.Response, error) {
+	m.reviews = append(m.reviews, review)
 	return m.resp, nil, nil
 }
 
@@ -46,3 +75,41 @@ func TestCreateReview(t *testing.T) {
 		}
 	})
 }
+
+func TestGreet(t *testing.T) {
+	webhookPayload := getMockPayload()
+	req, _ := http.NewRequest("POST", "/", bytes.NewBuffer(webhookPayload))
+	req.Header.Set("X-GitHub-Event", "pull_request")
+	event, _ := github.ParseWebHook(github.WebHookType(req), webhookPayload)
+	pr := event.(*github.PullRequestEvent).GetPullRequest()
+	t.Run("Test New Pull Requests Are Greeted", func(t *testing.T) {
+		mc := &mockClient{}
+		err := greet(pr, &pullRequestClient{ctx: context.Background(), client: mc}, "Welcome")
+		assert.Nil(t, err)
+		assert.Len(t, mc.reviews, 1)
+		assert.Equal(t, "Welcome", mc.reviews[0].GetBody())
+	})
+	t.Run("Test Retried Webhooks Do Not Greet Again", func(t *testing.T) {
+		mc := &mockClient{listReviews: []*github.PullRequestReview{{Body: github.String("Welcome")}}}
+		err := greet(pr, &pullRequestClient{ctx: context.Background(), client: mc}, "Welcome")
+		assert.Nil(t, err)
+		assert.Empty(t, mc.reviews)
+	})
+}
+
+func TestRemoveLGTM(t *testing.T) {
+	t.Run("Test Label Is Removed On New Commits", func(t *testing.T) {
+		mc := &mockIssueClient{labels: []string{"bug", "lgtm"}}
+		req := &commandRequest{
+			commandClients: commandClients{issueClient: &issueClient{ctx: context.Background(), client: mc}},
+			owner:          "Spazzy757",
+			repo:           "paul",
+			number:         1,
+			pullRequest:    true,
+			config:         &types.PaulConfig{},
+		}
+		err := removeLGTM(req)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"bug"}, mc.labels)
+	})
+}
@@ -0,0 +1,128 @@
+package github
+
+import (
+	"context"
+	"log"
+
+	"github.com/google/go-github/v32/github"
+)
+
+const (
+	reactionWorking   = "eyes"
+	reactionSucceeded = "+1"
+	reactionFailed    = "confused"
+)
+
+// commentKind is the kind of comment a command was given in,
+// each kind has its own reactions API
+type commentKind int
+
+const (
+	issueComment commentKind = iota
+	reviewComment
+)
+
+// interface to make testing logic easier
+type reactions interface {
+	CreateIssueCommentReaction(
+		ctx context.Context,
+		owner string,
+		repo string,
+		id int64,
+		content string,
+	) (*github.Reaction, *github.Response, error)
+	DeleteIssueCommentReaction(
+		ctx context.Context,
+		owner string,
+		repo string,
+		commentID int64,
+		reactionID int64,
+	) (*github.Response, error)
+	CreatePullRequestCommentReaction(
+		ctx context.Context,
+		owner string,
+		repo string,
+		id int64,
+		content string,
+	) (*github.Reaction, *github.Response, error)
+	DeletePullRequestCommentReaction(
+		ctx context.Context,
+		owner string,
+		repo string,
+		commentID int64,
+		reactionID int64,
+	) (*github.Response, error)
+}
+
+// struct to make testing logic easier
+type reactionClient struct {
+	ctx    context.Context
+	client reactions
+}
+
+/*
+runWithReactions runs a command and acknowledges it with reactions on the
+triggering comment when reactions are enabled: eyes while the command
+runs, then +1 when it succeeds or confused when it is unknown, disabled,
+not allowed or fails. Review bodies can not be reacted to so they have
+no comment id. Failing to react never stops the command
+*/
+func runWithReactions(r *registry, name string, req *commandRequest) error {
+	if !req.config.Reactions.Enabled || req.commentID == 0 {
+		return r.run(name, req)
+	}
+	cmd, ok := r.resolve(name, req.config)
+	if !ok {
+		react(req, reactionFailed)
+		return nil
+	}
+	working := react(req, reactionWorking)
+	allowed, err := cmd.execute(req)
+	if working != nil {
+		unreact(req, working)
+	}
+	if err != nil || !allowed {
+		react(req, reactionFailed)
+	} else {
+		react(req, reactionSucceeded)
+	}
+	return err
+}
+
+// react adds a reaction to the comment that triggered the command
+func react(req *commandRequest, content string) *github.Reaction {
+	create := req.reactionClient.client.CreateIssueCommentReaction
+	if req.commentKind == reviewComment {
+		create = req.reactionClient.client.CreatePullRequestCommentReaction
+	}
+	reaction, _, err := create(
+		req.reactionClient.ctx,
+		req.owner,
+		req.repo,
+		req.commentID,
+		content,
+	)
+	if err != nil {
+		log.Printf("An error occurred reacting with %v: %v", content, err)
+		return nil
+	}
+	return reaction
+}
+
+// unreact removes a reaction Paul added to the triggering comment
+func unreact(req *commandRequest, reaction *github.Reaction) {
+	remove := req.reactionClient.client.DeleteIssueCommentReaction
+	if req.commentKind == reviewComment {
+		remove = req.reactionClient.client.DeletePullRequestCommentReaction
+	}
+	_, err := remove(
+		req.reactionClient.ctx,
+		req.owner,
+		req.repo,
+		req.commentID,
+		reaction.GetID(),
+	)
+	if err != nil {
+		log.Printf("An error occurred removing reaction %v: %v", reaction.GetContent(), err)
+	}
+}
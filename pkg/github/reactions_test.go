@@ -0,0 +1,118 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Spazzy757/paul/pkg/types"
+	"github.com/google/go-github/v32/github"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockReactionClient struct {
+	reactions       []string
+	deleted         []int64
+	reviewReactions []string
+}
+
+func (m *mockReactionClient) CreatePullRequestCommentReaction(
+	ctx context.Context,
+	owner, repo string,
+	id int64,
+	content string,
+) (*github.Reaction, *github.Response, error) {
+	m.reviewReactions = append(m.reviewReactions, content)
+	return &github.Reaction{
+		ID:      github.Int64(int64(len(m.reviewReactions))),
+		Content: github.String(content),
+	}, nil, nil
+}
+
+func (m *mockReactionClient) DeletePullRequestCommentReaction(
+	ctx context.Context,
+	owner, repo string,
+	commentID, reactionID int64,
+) (*github.Response, error) {
+	m.deleted = append(m.deleted, reactionID)
+	return nil, nil
+}
+
+func (m *mockReactionClient) CreateIssueCommentReaction(
+	ctx context.Context,
+	owner, repo string,
+	id int64,
+	content string,
+) (*github.Reaction, *github.Response, error) {
+	m.reactions = append(m.reactions, content)
+	return &github.Reaction{
+		ID:      github.Int64(int64(len(m.reactions))),
+		Content: github.String(content),
+	}, nil, nil
+}
+
+func (m *mockReactionClient) DeleteIssueCommentReaction(
+	ctx context.Context,
+	owner, repo string,
+	commentID, reactionID int64,
+) (*github.Response, error) {
+	m.deleted = append(m.deleted, reactionID)
+	return nil, nil
+}
+
+func TestRunWithReactions(t *testing.T) {
+	r := newRegistry()
+	r.register(&Command{
+		Name:    "works",
+		Handler: func(req *commandRequest) error { return nil },
+	})
+	r.register(&Command{
+		Name:    "fails",
+		Handler: func(req *commandRequest) error { return fmt.Errorf("failed") },
+	})
+	r.register(&Command{
+		Name:       "restricted",
+		Permission: PermissionMaintainer,
+		Handler:    func(req *commandRequest) error { return nil },
+	})
+	r.register(&Command{
+		Name:      "disabled",
+		ConfigKey: "disabled",
+		Handler:   func(req *commandRequest) error { return nil },
+	})
+	newRequest := func(enabled bool) (*commandRequest, *mockReactionClient) {
+		req := getMockCommandRequest("cat-command", &mockIssueClient{}, &types.PaulConfig{
+			Reactions: types.Reactions{Enabled: enabled},
+		})
+		req.user = "stranger"
+		return req, req.reactionClient.client.(*mockReactionClient)
+	}
+	t.Run("Test Successful Command", func(t *testing.T) {
+		req, rc := newRequest(true)
+		assert.Nil(t, runWithReactions(r, "works", req))
+		assert.Equal(t, []string{"eyes", "+1"}, rc.reactions)
+		assert.Equal(t, []int64{1}, rc.deleted)
+	})
+	t.Run("Test Failed Command", func(t *testing.T) {
+		req, rc := newRequest(true)
+		assert.NotNil(t, runWithReactions(r, "fails", req))
+		assert.Equal(t, []string{"eyes", "confused"}, rc.reactions)
+	})
+	t.Run("Test Denied Command", func(t *testing.T) {
+		req, rc := newRequest(true)
+		assert.Nil(t, runWithReactions(r, "restricted", req))
+		assert.Equal(t, []string{"eyes", "confused"}, rc.reactions)
+	})
+	t.Run("Test Unknown And Disabled Commands", func(t *testing.T) {
+		req, rc := newRequest(true)
+		assert.Nil(t, runWithReactions(r, "unknown", req))
+		assert.Nil(t, runWithReactions(r, "disabled", req))
+		assert.Equal(t, []string{"confused", "confused"}, rc.reactions)
+	})
+	t.Run("Test Reactions Disabled", func(t *testing.T) {
+		req, rc := newRequest(false)
+		assert.Nil(t, runWithReactions(r, "works", req))
+		assert.Nil(t, runWithReactions(r, "unknown", req))
+		assert.Empty(t, rc.reactions)
+	})
+}
@@ -0,0 +1,34 @@
+package github
+
+import (
+	"fmt"
+
+	"github.com/google/go-github/v32/github"
+)
+
+func init() {
+	commands.register(&Command{
+		Name:        "retitle",
+		Args:        "new title",
+		Description: "Changes the title, the rest of the line is used as the title",
+		Permission:  PermissionAuthorOrMaintainer,
+		ConfigKey:   "retitle",
+		Handler:     handleRetitle,
+	})
+}
+
+// handleRetitle is the handler for the /retitle command
+func handleRetitle(req *commandRequest) error {
+	title := req.line
+	if err := req.config.Titles.Validate(title); err != nil {
+		return createComment(req, fmt.Sprintf("Sorry @%v, %v", req.user, err))
+	}
+	_, _, err := req.issueClient.client.Edit(
+		req.issueClient.ctx,
+		req.owner,
+		req.repo,
+		req.number,
+		&github.IssueRequest{Title: github.String(title)},
+	)
+	return err
+}
@@ -0,0 +1,45 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/Spazzy757/paul/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleRetitle(t *testing.T) {
+	cfg := &types.PaulConfig{Titles: types.Titles{Pattern: "^fix: "}}
+	t.Run("Test The Whole Line Is Used As The Title", func(t *testing.T) {
+		mc := &mockIssueClient{}
+		req := getMockCommandRequest("cat-command", mc, cfg)
+		inv := parseCommands("/retitle fix: don't   split this")[0]
+		req.args, req.line = inv.args, inv.line
+		assert.Nil(t, handleRetitle(req))
+		assert.Equal(t, "fix: don't   split this", mc.title)
+		assert.Empty(t, mc.comments)
+	})
+	t.Run("Test Titles Against The Policy Are Refused", func(t *testing.T) {
+		mc := &mockIssueClient{}
+		req := getMockCommandRequest("cat-command", mc, cfg)
+		req.line = "Fixed it"
+		assert.Nil(t, handleRetitle(req))
+		assert.Equal(t, "", mc.title)
+		assert.Contains(t, mc.comments[0], "must match the pattern")
+	})
+	t.Run("Test Empty Titles Are Refused", func(t *testing.T) {
+		mc := &mockIssueClient{}
+		req := getMockCommandRequest("cat-command", mc, &types.PaulConfig{})
+		assert.Nil(t, handleRetitle(req))
+		assert.Equal(t, "", mc.title)
+		assert.Contains(t, mc.comments[0], "can not be empty")
+	})
+	t.Run("Test Only The Author And Maintainers Can Retitle", func(t *testing.T) {
+		cmd, _ := commands.lookup("retitle")
+		permissions := map[string]string{"writer": "write"}
+		for user, expected := range map[string]bool{"author": true, "spazzy757": true, "writer": false} {
+			allowed, err := getMockPermissionRequest(user, "author", permissions).allowed(cmd.Permission)
+			assert.Nil(t, err)
+			assert.Equal(t, expected, allowed, user)
+		}
+	})
+}
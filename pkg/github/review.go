@@ -0,0 +1,83 @@
+package github
+
+import (
+	"fmt"
+
+	"github.com/google/go-github/v32/github"
+)
+
+func init() {
+	commands.register(&Command{
+		Name:        "lgtm",
+		Args:        "[cancel]",
+		Description: "Adds the lgtm label to a pull request, cancel removes it",
+		Permission:  PermissionCollaborator,
+		ConfigKey:   "lgtm",
+		Handler:     handleLGTM,
+	})
+	commands.register(&Command{
+		Name:        "approve",
+		Description: "Approves the pull request with a review from Paul",
+		Permission:  PermissionMaintainer,
+		ConfigKey:   "approve",
+		Handler:     handleApprove,
+	})
+}
+
+// handleLGTM is the handler for the /lgtm command
+func handleLGTM(req *commandRequest) error {
+	if ok, err := reviewable(req, "lgtm"); !ok {
+		return err
+	}
+	label := req.config.PullRequests.LGTMLabelName()
+	if len(req.args) > 0 && req.args[0] == "cancel" {
+		return removeLabel(req, label)
+	}
+	if _, err := ensureLabel(req, label, true); err != nil {
+		return err
+	}
+	_, _, err := req.issueClient.client.AddLabelsToIssue(
+		req.issueClient.ctx,
+		req.owner,
+		req.repo,
+		req.number,
+		[]string{label},
+	)
+	return err
+}
+
+// handleApprove is the handler for the /approve command
+func handleApprove(req *commandRequest) error {
+	if ok, err := reviewable(req, "approve"); !ok {
+		return err
+	}
+	_, _, err := req.pullRequestClient.client.CreateReview(
+		req.pullRequestClient.ctx,
+		req.owner,
+		req.repo,
+		req.number,
+		&github.PullRequestReviewRequest{
+			Body:  github.String(fmt.Sprintf("Approved on behalf of @%v", req.user)),
+			Event: github.String("APPROVE"),
+		},
+	)
+	return err
+}
+
+/*
+reviewable checks the review command is run on a pull request by someone
+other than the author, replying with the reason if it is not
+*/
+func reviewable(req *commandRequest, name string) (bool, error) {
+	if !req.pullRequest {
+		return false, createComment(req, fmt.Sprintf("`/%v` can only be used on pull requests", name))
+	}
+	if req.isAuthor(req.user) {
+		return false, createComment(req, fmt.Sprintf(
+			"Sorry @%v, you can not `/%v` your own pull request",
+			req.user,
+			name,
+		))
+	}
+	return true, nil
+}
@@ -0,0 +1,63 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleLGTM(t *testing.T) {
+	t.Run("Test LGTM Adds The Label", func(t *testing.T) {
+		req := getMockPermissionRequest("reviewer", "author", nil)
+		mc := req.issueClient.client.(*mockIssueClient)
+		assert.Nil(t, handleLGTM(req))
+		assert.Equal(t, []string{"lgtm"}, mc.labels)
+		assert.Len(t, mc.createdLabels, 1)
+	})
+	t.Run("Test LGTM Cancel Removes The Label", func(t *testing.T) {
+		req := getMockPermissionRequest("reviewer", "author", nil)
+		req.args = []string{"cancel"}
+		mc := req.issueClient.client.(*mockIssueClient)
+		mc.labels = []string{"lgtm"}
+		assert.Nil(t, handleLGTM(req))
+		assert.Empty(t, mc.labels)
+	})
+	t.Run("Test Author Can Not LGTM", func(t *testing.T) {
+		req := getMockPermissionRequest("author", "author", nil)
+		mc := req.issueClient.client.(*mockIssueClient)
+		assert.Nil(t, handleLGTM(req))
+		assert.Empty(t, mc.labels)
+		assert.Contains(t, mc.comments[0], "your own pull request")
+	})
+	t.Run("Test LGTM On Issues Is Refused", func(t *testing.T) {
+		req := getMockPermissionRequest("reviewer", "author", nil)
+		req.pullRequest = false
+		mc := req.issueClient.client.(*mockIssueClient)
+		assert.Nil(t, handleLGTM(req))
+		assert.Empty(t, mc.labels)
+		assert.Contains(t, mc.comments[0], "only be used on pull requests")
+	})
+}
+
+func TestHandleApprove(t *testing.T) {
+	t.Run("Test Approve Submits An Approving Review", func(t *testing.T) {
+		req := getMockPermissionRequest("Spazzy757", "author", nil)
+		pc := req.pullRequestClient.client.(*mockClient)
+		assert.Nil(t, handleApprove(req))
+		assert.Len(t, pc.reviews, 1)
+		assert.Equal(t, "APPROVE", pc.reviews[0].GetEvent())
+	})
+	t.Run("Test Author Can Not Approve", func(t *testing.T) {
+		req := getMockPermissionRequest("Spazzy757", "Spazzy757", nil)
+		pc := req.pullRequestClient.client.(*mockClient)
+		assert.Nil(t, handleApprove(req))
+		assert.Empty(t, pc.reviews)
+	})
+	t.Run("Test Only Maintainers Can Approve", func(t *testing.T) {
+		cmd, _ := commands.lookup("approve")
+		req := getMockPermissionRequest("admin", "author", map[string]string{"admin": "admin"})
+		allowed, err := req.allowed(cmd.Permission)
+		assert.Nil(t, err)
+		assert.False(t, allowed)
+	})
+}
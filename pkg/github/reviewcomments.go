@@ -0,0 +1,95 @@
+package github
+
+import (
+	"github.com/Spazzy757/paul/pkg/types"
+	"github.com/google/go-github/v32/github"
+)
+
+/*
+PullRequestReviewHandler handler for the pull request review event, runs
+the commands in the review summary and checks if the pull request can be
+merged now that it has been reviewed
+*/
+func PullRequestReviewHandler(event *github.PullRequestReviewEvent) error {
+	action := event.GetAction()
+	if action != "submitted" && action != "edited" {
+		return nil
+	}
+	client, ctx, cfg, err := getRepoClient(event.Installation.GetID(), event.Repo)
+	if err != nil {
+		return err
+	}
+	req := newReviewCommandRequest(event, newCommandClients(client, ctx), &cfg)
+	err = runComment(
+		req,
+		commentKey("pull_request_review", event.Review.GetID()),
+		event.Review.GetBody(),
+		"",
+	)
+	if err != nil {
+		return err
+	}
+	if cfg.Merge.Enabled && action == "submitted" {
+		return evaluateMerge(req)
+	}
+	return nil
+}
+
+//PullRequestReviewCommentHandler handler for the pull request review comment event
+func PullRequestReviewCommentHandler(event *github.PullRequestReviewCommentEvent) error {
+	action := event.GetAction()
+	if action != "created" && action != "edited" {
+		return nil
+	}
+	client, ctx, cfg, err := getRepoClient(event.Installation.GetID(), event.Repo)
+	if err != nil {
+		return err
+	}
+	req := newReviewCommentCommandRequest(event, newCommandClients(client, ctx), &cfg)
+	return runComment(
+		req,
+		commentKey("pull_request_review_comment", event.Comment.GetID()),
+		event.Comment.GetBody(),
+		previousBody(event.Changes),
+	)
+}
+
+// newReviewCommandRequest builds a commandRequest from a PullRequestReviewEvent,
+// review summaries can not be reacted to so the request has no comment id
+func newReviewCommandRequest(
+	event *github.PullRequestReviewEvent,
+	clients commandClients,
+	cfg *types.PaulConfig,
+) *commandRequest {
+	return &commandRequest{
+		commandClients: clients,
+		owner:          event.Repo.Owner.GetLogin(),
+		repo:           event.Repo.GetName(),
+		number:         event.PullRequest.GetNumber(),
+		user:           commandUser(event.GetAction(), event.Review.User, event.GetSender()),
+		issueAuthor:    event.PullRequest.User.GetLogin(),
+		pullRequest:    true,
+		config:         cfg,
+	}
+}
+
+// newReviewCommentCommandRequest builds a commandRequest from a
+// PullRequestReviewCommentEvent
+func newReviewCommentCommandRequest(
+	event *github.PullRequestReviewCommentEvent,
+	clients commandClients,
+	cfg *types.PaulConfig,
+) *commandRequest {
+	return &commandRequest{
+		commandClients: clients,
+		owner:          event.Repo.Owner.GetLogin(),
+		repo:           event.Repo.GetName(),
+		number:         event.PullRequest.GetNumber(),
+		commentID:      event.Comment.GetID(),
+		commentKind:    reviewComment,
+		user:           commandUser(event.GetAction(), event.Comment.User, event.GetSender()),
+		issueAuthor:    event.PullRequest.User.GetLogin(),
+		pullRequest:    true,
+		config:         cfg,
+	}
+}
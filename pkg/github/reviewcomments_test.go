@@ -0,0 +1,71 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Spazzy757/paul/pkg/types"
+	"github.com/google/go-github/v32/github"
+	"github.com/stretchr/testify/assert"
+)
+
+// getMockReviewEvent parses one of the review mock payloads
+func getMockReviewEvent(payloadType, eventType string) interface{} {
+	webhookPayload := getIssueCommentMockPayload(payloadType)
+	req, _ := http.NewRequest("POST", "/", bytes.NewBuffer(webhookPayload))
+	req.Header.Set("X-GitHub-Event", eventType)
+	event, _ := github.ParseWebHook(github.WebHookType(req), webhookPayload)
+	return event
+}
+
+func getMockClients(mc *mockIssueClient, rc *mockReactionClient) commandClients {
+	ctx := context.Background()
+	return commandClients{
+		issueClient:       &issueClient{ctx: ctx, client: mc},
+		repoClient:        &repositoryClient{ctx: ctx, client: &mockRepositoryClient{permissions: map[string]string{"reviewer": "write"}}},
+		pullRequestClient: &pullRequestClient{ctx: ctx, client: &mockClient{pullRequest: &github.PullRequest{}}},
+		checksClient:      &checksClient{ctx: ctx, client: &mockChecksClient{}},
+		reactionClient:    &reactionClient{ctx: ctx, client: rc},
+	}
+}
+
+func TestReviewCommands(t *testing.T) {
+	cfg := &types.PaulConfig{
+		Commands:  map[string]bool{"lgtm": true, "hold": true},
+		Reactions: types.Reactions{Enabled: true},
+	}
+	defer func(h *commandHistory) { history = h }(history)
+	t.Run("Test Commands In Review Summaries Run", func(t *testing.T) {
+		history = newCommandHistory(historyLimit)
+		event := getMockReviewEvent("pr-review", "pull_request_review").(*github.PullRequestReviewEvent)
+		mc := &mockIssueClient{}
+		rc := &mockReactionClient{}
+		req := newReviewCommandRequest(event, getMockClients(mc, rc), cfg)
+		assert.Equal(t, "reviewer", req.user)
+		assert.Equal(t, "Spazzy757", req.issueAuthor)
+		err := runComment(req, commentKey("pull_request_review", event.Review.GetID()), event.Review.GetBody(), "")
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"lgtm", "do-not-merge/hold"}, mc.labels)
+		assert.Empty(t, rc.reactions)
+		assert.Empty(t, rc.reviewReactions)
+	})
+	t.Run("Test Edited Review Comments Only Run New Commands", func(t *testing.T) {
+		history = newCommandHistory(historyLimit)
+		event := getMockReviewEvent("pr-review-comment", "pull_request_review_comment").(*github.PullRequestReviewCommentEvent)
+		mc := &mockIssueClient{}
+		rc := &mockReactionClient{}
+		req := newReviewCommentCommandRequest(event, getMockClients(mc, rc), cfg)
+		err := runComment(
+			req,
+			commentKey("pull_request_review_comment", event.Comment.GetID()),
+			event.Comment.GetBody(),
+			previousBody(event.Changes),
+		)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"do-not-merge/hold"}, mc.labels)
+		assert.Equal(t, []string{"eyes", "+1"}, rc.reviewReactions)
+		assert.Empty(t, rc.reactions)
+	})
+}
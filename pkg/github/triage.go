@@ -0,0 +1,126 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v32/github"
+)
+
+// lockReasons are the reasons GitHub accepts when locking a conversation
+var lockReasons = []string{"off-topic", "too heated", "resolved", "spam"}
+
+func init() {
+	commands.register(&Command{
+		Name:        "close",
+		Description: "Closes the issue or pull request",
+		Permission:  PermissionAuthorOrMaintainer,
+		ConfigKey:   "close",
+		Handler:     handleClose,
+	})
+	commands.register(&Command{
+		Name:        "reopen",
+		Description: "Reopens the issue or pull request",
+		Permission:  PermissionMaintainer,
+		ConfigKey:   "close",
+		Handler:     handleReopen,
+	})
+	commands.register(&Command{
+		Name:        "lock",
+		Args:        "[reason]",
+		Description: "Locks the conversation, reason is one of " + codeList(lockReasons),
+		Permission:  PermissionMaintainer,
+		ConfigKey:   "lock",
+		Handler:     handleLock,
+	})
+	commands.register(&Command{
+		Name:        "unlock",
+		Description: "Unlocks the conversation",
+		Permission:  PermissionMaintainer,
+		ConfigKey:   "lock",
+		Handler:     handleUnlock,
+	})
+}
+
+// handleClose is the handler for the /close command
+func handleClose(req *commandRequest) error {
+	if err := setState(req, "closed"); err != nil {
+		return err
+	}
+	return createComment(req, fmt.Sprintf("Closed by @%v", req.user))
+}
+
+// handleReopen is the handler for the /reopen command
+func handleReopen(req *commandRequest) error {
+	if err := setState(req, "open"); err != nil {
+		return err
+	}
+	return createComment(req, fmt.Sprintf("Reopened by @%v", req.user))
+}
+
+// setState opens or closes the issue/pull request
+func setState(req *commandRequest, state string) error {
+	_, _, err := req.issueClient.client.Edit(
+		req.issueClient.ctx,
+		req.owner,
+		req.repo,
+		req.number,
+		&github.IssueRequest{State: github.String(state)},
+	)
+	return err
+}
+
+/*
+handleLock is the handler for the /lock command, the confirmation is sent
+before locking so it is part of the conversation it explains
+*/
+func handleLock(req *commandRequest) error {
+	reason := strings.ToLower(strings.Join(req.args, " "))
+	opts := &github.LockIssueOptions{}
+	message := fmt.Sprintf("Conversation locked by @%v", req.user)
+	if reason != "" {
+		if !validLockReason(reason) {
+			return createComment(req, fmt.Sprintf(
+				"`%v` is not a valid reason to lock, valid reasons are: %v",
+				reason,
+				codeList(lockReasons),
+			))
+		}
+		opts.LockReason = reason
+		message = fmt.Sprintf("%v as %v", message, reason)
+	}
+	if err := createComment(req, message); err != nil {
+		return err
+	}
+	_, err := req.issueClient.client.Lock(
+		req.issueClient.ctx,
+		req.owner,
+		req.repo,
+		req.number,
+		opts,
+	)
+	return err
+}
+
+// handleUnlock is the handler for the /unlock command
+func handleUnlock(req *commandRequest) error {
+	_, err := req.issueClient.client.Unlock(
+		req.issueClient.ctx,
+		req.owner,
+		req.repo,
+		req.number,
+	)
+	if err != nil {
+		return err
+	}
+	return createComment(req, fmt.Sprintf("Conversation unlocked by @%v", req.user))
+}
+
+func validLockReason(reason string) bool {
+	for _, valid := range lockReasons {
+		if reason == valid {
+			return true
+		}
+	}
+	return false
+}
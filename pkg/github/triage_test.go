@@ -0,0 +1,74 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/Spazzy757/paul/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleCloseAndReopen(t *testing.T) {
+	mc := &mockIssueClient{}
+	req := getMockCommandRequest("cat-command", mc, &types.PaulConfig{})
+	t.Run("Test Close", func(t *testing.T) {
+		assert.Nil(t, handleClose(req))
+		assert.Equal(t, "closed", mc.state)
+		assert.Equal(t, "Closed by @Spazzy757", mc.comments[len(mc.comments)-1])
+	})
+	t.Run("Test Reopen", func(t *testing.T) {
+		assert.Nil(t, handleReopen(req))
+		assert.Equal(t, "open", mc.state)
+		assert.Equal(t, "Reopened by @Spazzy757", mc.comments[len(mc.comments)-1])
+	})
+	t.Run("Test Author Can Close But Not Reopen Their Own Item", func(t *testing.T) {
+		req := getMockPermissionRequest("author", "author", nil)
+		closeCmd, _ := commands.lookup("close")
+		allowed, err := req.allowed(closeCmd.Permission)
+		assert.Nil(t, err)
+		assert.True(t, allowed)
+		reopenCmd, _ := commands.lookup("reopen")
+		allowed, err = req.allowed(reopenCmd.Permission)
+		assert.Nil(t, err)
+		assert.False(t, allowed)
+	})
+	t.Run("Test Collaborators Can Not Close Other Items", func(t *testing.T) {
+		req := getMockPermissionRequest("writer", "author", map[string]string{"writer": "write"})
+		closeCmd, _ := commands.lookup("close")
+		allowed, err := req.allowed(closeCmd.Permission)
+		assert.Nil(t, err)
+		assert.False(t, allowed)
+	})
+}
+
+func TestHandleLock(t *testing.T) {
+	t.Run("Test Lock Without Reason", func(t *testing.T) {
+		mc := &mockIssueClient{}
+		req := getMockCommandRequest("cat-command", mc, &types.PaulConfig{})
+		assert.Nil(t, handleLock(req))
+		assert.True(t, mc.locked)
+		assert.Equal(t, "", mc.lockReason)
+	})
+	t.Run("Test Lock With Reason", func(t *testing.T) {
+		mc := &mockIssueClient{}
+		req := getMockCommandRequest("cat-command", mc, &types.PaulConfig{})
+		req.args = []string{"Too", "heated"}
+		assert.Nil(t, handleLock(req))
+		assert.True(t, mc.locked)
+		assert.Equal(t, "too heated", mc.lockReason)
+		assert.Contains(t, mc.comments[0], "as too heated")
+	})
+	t.Run("Test Lock With Invalid Reason", func(t *testing.T) {
+		mc := &mockIssueClient{}
+		req := getMockCommandRequest("cat-command", mc, &types.PaulConfig{})
+		req.args = []string{"boring"}
+		assert.Nil(t, handleLock(req))
+		assert.False(t, mc.locked)
+		assert.Contains(t, mc.comments[0], "not a valid reason")
+	})
+	t.Run("Test Unlock", func(t *testing.T) {
+		mc := &mockIssueClient{locked: true}
+		req := getMockCommandRequest("cat-command", mc, &types.PaulConfig{})
+		assert.Nil(t, handleUnlock(req))
+		assert.False(t, mc.locked)
+	})
+}
@@ -1,35 +1,173 @@
 package github
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
 	"log"
 	"net/http"
 
-	"github.com/Spazzy757/paul/pkg/helpers"
+	"github.com/Spazzy757/paul/pkg/config"
+	"github.com/Spazzy757/paul/pkg/queue"
 	"github.com/google/go-github/v32/github"
 )
 
-//IncomingWebhook handles an incoming webhook request
-func IncomingWebhook(r *http.Request) error {
+// errNoSecret rejects webhooks when there is no secret to check them with
+var errNoSecret = errors.New("no webhook secret is configured, set INSECURE_DEV=true to accept unsigned webhooks")
+
+// Queue is where validated webhooks are sent to be processed
+type Queue interface {
+	Enqueue(job queue.Job) error
+}
+
+/*
+IncomingWebhook validates an incoming webhook request and queues it to be
+processed by ProcessJob, errors are returned as an *Error so the caller
+can use StatusCode to respond to GitHub. Deliveries that were already
+accepted are skipped so redelivered webhooks are not processed twice,
+unless processing them failed
+*/
+func IncomingWebhook(r *http.Request, q Queue) error {
 	// handle authentication
-	secret_key := helpers.GetEnv("SECRET_KEY", "")
-	payload, validationErr := github.ValidatePayload(r, []byte(secret_key))
-	if validationErr != nil {
-		return validationErr
-	}
-	event, err := github.ParseWebHook(github.WebHookType(r), payload)
+	payload, err := validatePayload(r)
 	if err != nil {
+		return err
+	}
+	// parse the event up front so invalid payloads are rejected now
+	// rather than failing in a worker
+	if _, err := github.ParseWebHook(github.WebHookType(r), payload); err != nil {
 		log.Printf("could not parse webhook: err=%s\n", err)
+		return validationError(err)
+	}
+	job := queue.Job{
+		ID:      github.DeliveryID(r),
+		Key:     jobKey(payload),
+		Event:   github.WebHookType(r),
+		Payload: payload,
+	}
+	if job.ID != "" {
+		seen, err := deliveries.Seen(job.ID)
+		if err != nil {
+			log.Printf("could not check delivery %v, processing it anyway: err=%s\n", job.ID, err)
+		}
+		if seen {
+			log.Printf("skipping delivery %v, it was already accepted\n", job.ID)
+			return nil
+		}
+	}
+	if err := q.Enqueue(job); err != nil {
+		// let GitHub redeliver the webhook once there is room
+		forgetDelivery(job.ID)
+		return queueError(err)
+	}
+	return nil
+}
+
+/*
+validatePayload returns the payload of a webhook signed with any of the
+accepted webhook secrets so secrets can be rotated without rejecting
+webhooks signed with the previous secret. Without secrets every webhook
+is rejected unless INSECURE_DEV is set
+*/
+func validatePayload(r *http.Request) ([]byte, error) {
+	secrets, err := config.WebhookSecrets()
+	if err != nil {
+		return nil, err
+	}
+	// the signature is of the raw body, which differs from the payload
+	// for form encoded webhooks
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, validationError(err)
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	payload, err := github.ValidatePayload(r, nil)
+	if err != nil {
+		return nil, validationError(err)
+	}
+	if len(secrets) == 0 {
+		if config.InsecureDev() {
+			return payload, nil
+		}
+		return nil, validationError(errNoSecret)
+	}
+	signature := r.Header.Get("X-Hub-Signature")
+	for _, secret := range secrets {
+		if err = github.ValidateSignature(signature, body, []byte(secret)); err == nil {
+			return payload, nil
+		}
+	}
+	return nil, validationError(err)
+}
+
+// ProcessJob runs the handler for a queued webhook
+func ProcessJob(job queue.Job) error {
+	event, err := github.ParseWebHook(job.Event, job.Payload)
+	if err != nil {
+		return validationError(err)
+	}
+	return handleEvent(job.Event, event)
+}
+
+/*
+ForgetFailedDeliveries wraps the handler of the queue so deliveries that
+failed for good, whether they were dropped or dead-lettered, are forgotten
+and processed again when they are redelivered from GitHub
+*/
+func ForgetFailedDeliveries(handler queue.Handler) queue.Handler {
+	return func(job queue.Job) error {
+		err := handler(job)
+		var retryErr *queue.RetryError
+		if err != nil && !errors.As(err, &retryErr) {
+			forgetDelivery(job.ID)
+		}
 		return err
 	}
+}
+
+// forgetDelivery lets a delivery be accepted again when it is redelivered
+func forgetDelivery(id string) {
+	if id == "" {
+		return
+	}
+	if err := deliveries.Forget(id); err != nil {
+		log.Printf("could not forget delivery %v: err=%s\n", id, err)
+	}
+}
 
+// jobKey orders jobs by repository so events for a repo are handled in order
+func jobKey(payload []byte) string {
+	var event struct {
+		Repository struct {
+			FullName string `json:"full_name"`
+		} `json:"repository"`
+	}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return ""
+	}
+	return event.Repository.FullName
+}
+
+// handleEvent runs the handler for the type of event
+func handleEvent(eventType string, event interface{}) error {
 	switch e := event.(type) {
 	case *github.IssueCommentEvent:
-		IssueCommentHandler(e)
+		return IssueCommentHandler(e)
 	case *github.PullRequestEvent:
-		PullRequestHandler(e)
+		return PullRequestHandler(e)
+	case *github.PullRequestReviewEvent:
+		return PullRequestReviewHandler(e)
+	case *github.PullRequestReviewCommentEvent:
+		return PullRequestReviewCommentHandler(e)
+	case *github.CheckSuiteEvent:
+		return CheckSuiteHandler(e)
+	case *github.StatusEvent:
+		return StatusHandler(e)
+	case *github.PushEvent:
+		return PushHandler(e)
 	default:
-		log.Printf("unknown event type %s\n", github.WebHookType(r))
+		log.Printf("unknown event type %s\n", eventType)
 		return nil
 	}
-	return nil
 }
@@ -0,0 +1,158 @@
+package github
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Spazzy757/paul/pkg/queue"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockQueue struct {
+	jobs []queue.Job
+	err  error
+}
+
+func (m *mockQueue) Enqueue(job queue.Job) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.jobs = append(m.jobs, job)
+	return nil
+}
+
+func sign(payload []byte, secret string) string {
+	mac := hmac.New(sha1.New, []byte(secret))
+	mac.Write(payload)
+	return "sha1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func getMockWebhookRequest(payload []byte, signature, deliveryID string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/webhooks", bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Event", "issue_comment")
+	req.Header.Set("X-GitHub-Delivery", deliveryID)
+	req.Header.Set("X-Hub-Signature", signature)
+	return req
+}
+
+func TestIncomingWebhook(t *testing.T) {
+	os.Setenv("SECRET_KEY", "secret")
+	defer os.Unsetenv("SECRET_KEY")
+	payload := getIssueCommentMockPayload("cat-command")
+	defer UseDeliveryStore(deliveries)
+
+	t.Run("Test valid webhooks are queued", func(t *testing.T) {
+		UseDeliveryStore(NewMemoryDeliveryStore(10, time.Hour))
+		q := &mockQueue{}
+		err := IncomingWebhook(getMockWebhookRequest(payload, sign(payload, "secret"), "delivery-1"), q)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(q.jobs))
+		assert.Equal(t, "delivery-1", q.jobs[0].ID)
+		assert.Equal(t, "Spazzy757/paul", q.jobs[0].Key)
+		assert.Equal(t, "issue_comment", q.jobs[0].Event)
+	})
+
+	t.Run("Test invalid signatures are rejected", func(t *testing.T) {
+		UseDeliveryStore(NewMemoryDeliveryStore(10, time.Hour))
+		q := &mockQueue{}
+		err := IncomingWebhook(getMockWebhookRequest(payload, sign(payload, "wrong"), "delivery-1"), q)
+		assert.Equal(t, ErrorKindValidation, Classify(err))
+		assert.Equal(t, 0, len(q.jobs))
+	})
+
+	t.Run("Test webhooks signed with a previous secret are queued", func(t *testing.T) {
+		os.Setenv("SECRET_KEY", "next,secret")
+		defer os.Setenv("SECRET_KEY", "secret")
+		UseDeliveryStore(NewMemoryDeliveryStore(10, time.Hour))
+		q := &mockQueue{}
+		err := IncomingWebhook(getMockWebhookRequest(payload, sign(payload, "next"), "delivery-1"), q)
+		assert.NoError(t, err)
+		err = IncomingWebhook(getMockWebhookRequest(payload, sign(payload, "secret"), "delivery-2"), q)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(q.jobs))
+	})
+
+	t.Run("Test unsigned webhooks are rejected without a secret", func(t *testing.T) {
+		os.Unsetenv("SECRET_KEY")
+		defer os.Setenv("SECRET_KEY", "secret")
+		UseDeliveryStore(NewMemoryDeliveryStore(10, time.Hour))
+		q := &mockQueue{}
+		err := IncomingWebhook(getMockWebhookRequest(payload, "", "delivery-1"), q)
+		assert.Equal(t, ErrorKindValidation, Classify(err))
+		assert.True(t, errors.Is(err, errNoSecret))
+		assert.Equal(t, 0, len(q.jobs))
+	})
+
+	t.Run("Test unsigned webhooks are accepted in insecure dev mode", func(t *testing.T) {
+		os.Unsetenv("SECRET_KEY")
+		defer os.Setenv("SECRET_KEY", "secret")
+		os.Setenv("INSECURE_DEV", "true")
+		defer os.Unsetenv("INSECURE_DEV")
+		UseDeliveryStore(NewMemoryDeliveryStore(10, time.Hour))
+		q := &mockQueue{}
+		err := IncomingWebhook(getMockWebhookRequest(payload, "", "delivery-1"), q)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(q.jobs))
+	})
+
+	t.Run("Test full queues are reported", func(t *testing.T) {
+		UseDeliveryStore(NewMemoryDeliveryStore(10, time.Hour))
+		q := &mockQueue{err: errors.New("queue is full")}
+		err := IncomingWebhook(getMockWebhookRequest(payload, sign(payload, "secret"), "delivery-1"), q)
+		assert.Equal(t, ErrorKindQueue, Classify(err))
+		// the delivery is accepted once the queue has room
+		q.err = nil
+		err = IncomingWebhook(getMockWebhookRequest(payload, sign(payload, "secret"), "delivery-1"), q)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(q.jobs))
+	})
+
+	t.Run("Test redelivered webhooks are skipped", func(t *testing.T) {
+		UseDeliveryStore(NewMemoryDeliveryStore(10, time.Hour))
+		q := &mockQueue{}
+		for _, id := range []string{"delivery-1", "delivery-2", "delivery-1"} {
+			err := IncomingWebhook(getMockWebhookRequest(payload, sign(payload, "secret"), id), q)
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, 2, len(q.jobs))
+		assert.Equal(t, "delivery-1", q.jobs[0].ID)
+		assert.Equal(t, "delivery-2", q.jobs[1].ID)
+	})
+}
+
+func TestForgetFailedDeliveries(t *testing.T) {
+	defer UseDeliveryStore(deliveries)
+	var tests = []struct {
+		name        string
+		err         error
+		redelivered bool
+	}{
+		{"Test Processed Deliveries Are Remembered", nil, false},
+		{"Test Deliveries Waiting For A Retry Are Remembered", &queue.RetryError{Delay: time.Second, Err: errors.New("boom")}, false},
+		{"Test Failed Deliveries Are Forgotten", errors.New("dead-lettered after 5 attempts: boom"), true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			UseDeliveryStore(NewMemoryDeliveryStore(10, time.Hour))
+			seen, err := deliveries.Seen("delivery-1")
+			assert.NoError(t, err)
+			assert.False(t, seen)
+			handler := ForgetFailedDeliveries(func(job queue.Job) error {
+				return test.err
+			})
+			assert.Equal(t, test.err, handler(queue.Job{ID: "delivery-1"}))
+			seen, err = deliveries.Seen("delivery-1")
+			assert.NoError(t, err)
+			assert.Equal(t, test.redelivered, !seen)
+		})
+	}
+}
@@ -33,38 +33,26 @@ func GetEnv(key, fallback string) string {
 	return fallback
 }
 
-//GetAccessToken returns a Github OAuth Token
-func GetAccessToken(config config.Config, installationID int64) (string, error) {
-	token := os.Getenv("PERSONAL_ACCESS_TOKEN")
-	if len(token) == 0 {
-		installationToken, tokenErr := makeAccessTokenForInstallation(
-			config.ApplicationID,
-			installationID,
-			config.PrivateKey,
-		)
-		if tokenErr != nil {
-			return "", tokenErr
-		}
-		token = installationToken
-	}
-	return token, nil
-}
-
 // MakeAccessTokenForInstallation makes an access token for an installation / private key
-func makeAccessTokenForInstallation(appID string, installation int64, privateKey string) (string, error) {
+// using the API at apiURL, github.com is used when it is empty
+func makeAccessTokenForInstallation(apiURL, appID string, installation int64, privateKey string) (JWTAuth, error) {
+	jwtAuth := JWTAuth{}
 	signed, err := getSignedJwtToken(appID, privateKey)
 
 	if err != nil {
 		msg := fmt.Sprintf("can't run GetSignedJwtToken for app_id: %s and installation_id: %d, error: %v", appID, installation, err)
 
 		fmt.Printf("Error %s\n", msg)
-		return "", err
+		return jwtAuth, err
 	}
 
+	if apiURL == "" {
+		apiURL = config.DefaultAPIURL
+	}
 	req, err := http.NewRequest(http.MethodPost,
-		fmt.Sprintf("https://api.github.com/app/installations/%d/access_tokens", installation), nil)
+		fmt.Sprintf("%sapp/installations/%d/access_tokens", apiURL, installation), nil)
 	if err != nil {
-		return "", err
+		return jwtAuth, err
 	}
 
 	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", signed))
@@ -75,22 +63,26 @@ func makeAccessTokenForInstallation(appID string, installation int64, privateKey
 	if err != nil {
 		msg := fmt.Sprintf("can't get access_token for app_id: %s and installation_id: %d error: %v", appID, installation, err)
 		fmt.Printf("Error: %s\n", msg)
-		return "", fmt.Errorf("%s", msg)
+		return jwtAuth, fmt.Errorf("%s", msg)
 	}
 
 	defer res.Body.Close()
 
 	bytesOut, readErr := ioutil.ReadAll(res.Body)
 	if readErr != nil {
-		return "", readErr
+		return jwtAuth, readErr
+	}
+
+	if res.StatusCode != http.StatusCreated {
+		return jwtAuth, fmt.Errorf("can't get access_token for app_id: %s and installation_id: %d, status: %d, body: %s",
+			appID, installation, res.StatusCode, bytesOut)
 	}
 
-	jwtAuth := JWTAuth{}
 	jsonErr := json.Unmarshal(bytesOut, &jwtAuth)
 	if jsonErr != nil {
-		return "", jsonErr
+		return jwtAuth, jsonErr
 	}
-	return jwtAuth.Token, nil
+	return jwtAuth, nil
 }
 
 // GetSignedJwtToken get a tokens signed with private key
@@ -1,12 +1,17 @@
 package helpers
 
 import (
-	"github.com/Spazzy757/paul/pkg/config"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
 	"github.com/stretchr/testify/assert"
-	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
-	"path"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestGetEnv(t *testing.T) {
@@ -21,33 +26,40 @@ func TestGetEnv(t *testing.T) {
 	})
 }
 
-func TestAccessToken(t *testing.T) {
-	privateWant := "private"
-	secretWant := "secret"
-	appIDWant := "321"
-	tmpDir := os.TempDir()
-
-	ioutil.WriteFile(path.Join(tmpDir, "paul-private-key"), []byte(privateWant), 0600)
-	ioutil.WriteFile(path.Join(tmpDir, "paul-secret-key"), []byte(secretWant), 0600)
-
-	defer os.RemoveAll(path.Join(tmpDir, "paul-private-key"))
-	defer os.RemoveAll(path.Join(tmpDir, "paul-secret-key"))
-	os.Setenv("SECRET_PATH", tmpDir)
-	os.Setenv("APPLICATION_ID", appIDWant)
-	t.Run("Test set Environment Returns Personal Token token", func(t *testing.T) {
-		os.Setenv("PERSONAL_ACCESS_TOKEN", "123456789")
-
-		cfg, _ := config.NewConfig()
+func getMockPrivateKey(t *testing.T) string {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	}))
+}
 
-		token, _ := GetAccessToken(cfg, 1)
-		assert.Equal(t, token, "123456789")
+func TestMakeAccessTokenForInstallation(t *testing.T) {
+	privateKey := getMockPrivateKey(t)
+	t.Run("Test Token Is Minted With The Configured API", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "/api/v3/app/installations/1/access_tokens", r.URL.Path)
+			assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "Bearer "))
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"token":"v1.abc","expires_at":"2020-10-01T01:00:00Z"}`))
+		}))
+		defer server.Close()
+		auth, err := makeAccessTokenForInstallation(server.URL+"/api/v3/", "321", 1, privateKey)
+		assert.Nil(t, err)
+		assert.Equal(t, "v1.abc", auth.Token)
+		assert.Equal(t, time.Date(2020, 10, 1, 1, 0, 0, 0, time.UTC), auth.ExpiresAt)
 	})
-	t.Run("Test Unset Environment Err", func(t *testing.T) {
-		os.Unsetenv("PERSONAL_ACCESS_TOKEN")
-
-		cfg, _ := config.NewConfig()
-
-		token, _ := GetAccessToken(cfg, 1)
-		assert.Equal(t, token, "")
+	t.Run("Test Failed Responses Return An Error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message":"Bad credentials"}`))
+		}))
+		defer server.Close()
+		_, err := makeAccessTokenForInstallation(server.URL+"/", "321", 1, privateKey)
+		assert.NotNil(t, err)
 	})
 }
@@ -0,0 +1,95 @@
+package helpers
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Spazzy757/paul/pkg/config"
+	"golang.org/x/oauth2"
+)
+
+// tokenRefreshMargin is how long before it expires a token is replaced
+const tokenRefreshMargin = 5 * time.Minute
+
+/*
+TokenManager hands out installation access tokens, tokens are cached per
+installation and only minted again shortly before they expire
+*/
+type TokenManager struct {
+	mu     sync.Mutex
+	tokens map[int64]*installationToken
+	mint   func(installationID int64) (JWTAuth, error)
+	now    func() time.Time
+}
+
+// installationToken is the cached token of an installation, the lock
+// makes concurrent refreshes of an installation mint a single token
+type installationToken struct {
+	sync.Mutex
+	auth JWTAuth
+}
+
+// NewTokenManager creates a TokenManager minting tokens for the app in cfg
+func NewTokenManager(cfg config.Config) *TokenManager {
+	return &TokenManager{
+		tokens: map[int64]*installationToken{},
+		mint: func(installationID int64) (JWTAuth, error) {
+			return makeAccessTokenForInstallation(cfg.APIURL, cfg.ApplicationID, installationID, cfg.PrivateKey)
+		},
+		now: time.Now,
+	}
+}
+
+/*
+Token returns a token for the installation that is valid for at least
+tokenRefreshMargin, PERSONAL_ACCESS_TOKEN is used instead when it is set
+*/
+func (m *TokenManager) Token(installationID int64) (JWTAuth, error) {
+	if token := os.Getenv("PERSONAL_ACCESS_TOKEN"); len(token) > 0 {
+		return JWTAuth{Token: token}, nil
+	}
+	m.mu.Lock()
+	cached, ok := m.tokens[installationID]
+	if !ok {
+		cached = &installationToken{}
+		m.tokens[installationID] = cached
+	}
+	m.mu.Unlock()
+
+	cached.Lock()
+	defer cached.Unlock()
+	if cached.auth.Token != "" && m.now().Add(tokenRefreshMargin).Before(cached.auth.ExpiresAt) {
+		return cached.auth, nil
+	}
+	auth, err := m.mint(installationID)
+	if err != nil {
+		return JWTAuth{}, err
+	}
+	cached.auth = auth
+	return auth, nil
+}
+
+// TokenSource returns an oauth2.TokenSource for the installation
+func (m *TokenManager) TokenSource(installationID int64) oauth2.TokenSource {
+	return &installationTokenSource{manager: m, installationID: installationID}
+}
+
+type installationTokenSource struct {
+	manager        *TokenManager
+	installationID int64
+}
+
+// Token returns the token of the installation, it expires early so
+// clients ask for a new one when the manager would replace it
+func (s *installationTokenSource) Token() (*oauth2.Token, error) {
+	auth, err := s.manager.Token(s.installationID)
+	if err != nil {
+		return nil, err
+	}
+	token := &oauth2.Token{AccessToken: auth.Token}
+	if !auth.ExpiresAt.IsZero() {
+		token.Expiry = auth.ExpiresAt.Add(-tokenRefreshMargin)
+	}
+	return token, nil
+}
@@ -0,0 +1,105 @@
+package helpers
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getMockTokenManager(now *time.Time) (*TokenManager, *int) {
+	var mu sync.Mutex
+	minted := 0
+	m := &TokenManager{
+		tokens: map[int64]*installationToken{},
+		mint: func(installationID int64) (JWTAuth, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			minted++
+			return JWTAuth{
+				Token:     fmt.Sprintf("token-%d-%d", installationID, minted),
+				ExpiresAt: now.Add(time.Hour),
+			}, nil
+		},
+		now: func() time.Time { return *now },
+	}
+	return m, &minted
+}
+
+func TestTokenManager(t *testing.T) {
+	os.Unsetenv("PERSONAL_ACCESS_TOKEN")
+	t.Run("Test Tokens Are Cached Until They Are About To Expire", func(t *testing.T) {
+		now := time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)
+		m, minted := getMockTokenManager(&now)
+		first, err := m.Token(1)
+		assert.Nil(t, err)
+		now = now.Add(50 * time.Minute)
+		second, err := m.Token(1)
+		assert.Nil(t, err)
+		assert.Equal(t, first, second)
+		assert.Equal(t, 1, *minted)
+		now = now.Add(6 * time.Minute)
+		third, err := m.Token(1)
+		assert.Nil(t, err)
+		assert.NotEqual(t, first.Token, third.Token)
+		assert.Equal(t, 2, *minted)
+	})
+	t.Run("Test Installations Have Their Own Tokens", func(t *testing.T) {
+		now := time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)
+		m, minted := getMockTokenManager(&now)
+		one, _ := m.Token(1)
+		two, _ := m.Token(2)
+		assert.Equal(t, "token-1-1", one.Token)
+		assert.Equal(t, "token-2-2", two.Token)
+		assert.Equal(t, 2, *minted)
+	})
+	t.Run("Test Concurrent Requests Mint One Token", func(t *testing.T) {
+		now := time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)
+		m, minted := getMockTokenManager(&now)
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				m.Token(1)
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, 1, *minted)
+	})
+	t.Run("Test Failures Are Not Cached", func(t *testing.T) {
+		now := time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)
+		m, _ := getMockTokenManager(&now)
+		mint := m.mint
+		m.mint = func(installationID int64) (JWTAuth, error) {
+			return JWTAuth{}, fmt.Errorf("bad credentials")
+		}
+		_, err := m.Token(1)
+		assert.EqualError(t, err, "bad credentials")
+		m.mint = mint
+		token, err := m.Token(1)
+		assert.Nil(t, err)
+		assert.Equal(t, "token-1-1", token.Token)
+	})
+	t.Run("Test Personal Access Token Is Used When Set", func(t *testing.T) {
+		os.Setenv("PERSONAL_ACCESS_TOKEN", "123456789")
+		defer os.Unsetenv("PERSONAL_ACCESS_TOKEN")
+		now := time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)
+		m, minted := getMockTokenManager(&now)
+		token, err := m.Token(1)
+		assert.Nil(t, err)
+		assert.Equal(t, "123456789", token.Token)
+		assert.Equal(t, 0, *minted)
+	})
+	t.Run("Test Token Source Expires Before The Token", func(t *testing.T) {
+		now := time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)
+		m, _ := getMockTokenManager(&now)
+		token, err := m.TokenSource(1).Token()
+		assert.Nil(t, err)
+		assert.Equal(t, "token-1-1", token.AccessToken)
+		assert.Equal(t, now.Add(time.Hour-tokenRefreshMargin), token.Expiry)
+	})
+}
@@ -0,0 +1,216 @@
+package queue
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned when a dead letter does not exist
+var ErrNotFound = errors.New("dead letter not found")
+
+// DeadLetter is a job that kept failing and was set aside to be replayed
+type DeadLetter struct {
+	Job      Job       `json:"job"`
+	Error    string    `json:"error"`
+	Attempts int       `json:"attempts"`
+	FailedAt time.Time `json:"failed_at"`
+}
+
+// DeadLetterStore keeps dead letters until they are replayed
+type DeadLetterStore interface {
+	// Add stores a dead letter, replacing one with the same job id
+	Add(letter DeadLetter) error
+	// List returns the dead letters in the order they were added
+	List() ([]DeadLetter, error)
+	// Get returns the dead letter for a job id or ErrNotFound
+	Get(id string) (DeadLetter, error)
+	// Remove deletes the dead letter for a job id
+	Remove(id string) error
+}
+
+// record is a line in the dead letter file, removals are kept as tombstones
+type record struct {
+	ID      string      `json:"id"`
+	Letter  *DeadLetter `json:"letter,omitempty"`
+	Removed bool        `json:"removed,omitempty"`
+}
+
+/*
+FileStore is a DeadLetterStore backed by an append-only file of JSON
+lines so dead letters survive restarts. Every change is appended and
+synced to disk, the file is compacted when it is opened
+*/
+type FileStore struct {
+	mu      sync.Mutex
+	path    string
+	file    *os.File
+	letters map[string]DeadLetter
+	order   []string
+}
+
+// OpenFileStore loads the dead letters in path, creating it if needed
+func OpenFileStore(path string) (*FileStore, error) {
+	s := &FileStore{path: path, letters: map[string]DeadLetter{}}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	if err := s.compact(); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open dead letters: %w", err)
+	}
+	s.file = file
+	return s, nil
+}
+
+// load replays the records in the file, a torn last line is skipped
+func (s *FileStore) load() error {
+	file, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to read dead letters: %w", err)
+	}
+	defer file.Close()
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			var rec record
+			if jsonErr := json.Unmarshal(line, &rec); jsonErr != nil {
+				log.Printf("Skipping unreadable dead letter in %v: %v", s.path, jsonErr)
+			} else {
+				s.apply(rec)
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("unable to read dead letters: %w", err)
+		}
+	}
+}
+
+// compact rewrites the file with only the dead letters that are left
+func (s *FileStore) compact() error {
+	tmp := s.path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("unable to compact dead letters: %w", err)
+	}
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for _, id := range s.order {
+		letter := s.letters[id]
+		if err := encoder.Encode(record{ID: id, Letter: &letter}); err != nil {
+			file.Close()
+			return fmt.Errorf("unable to compact dead letters: %w", err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return fmt.Errorf("unable to compact dead letters: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return fmt.Errorf("unable to compact dead letters: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("unable to compact dead letters: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("unable to compact dead letters: %w", err)
+	}
+	return nil
+}
+
+// apply updates the in memory view with a record
+func (s *FileStore) apply(rec record) {
+	if _, ok := s.letters[rec.ID]; ok {
+		delete(s.letters, rec.ID)
+		for i, id := range s.order {
+			if id == rec.ID {
+				s.order = append(s.order[:i], s.order[i+1:]...)
+				break
+			}
+		}
+	}
+	if rec.Removed || rec.Letter == nil {
+		return
+	}
+	s.letters[rec.ID] = *rec.Letter
+	s.order = append(s.order, rec.ID)
+}
+
+// append writes a record to the end of the file before applying it
+func (s *FileStore) append(rec record) error {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("unable to write dead letter: %w", err)
+	}
+	if err := s.file.Sync(); err != nil {
+		return fmt.Errorf("unable to write dead letter: %w", err)
+	}
+	s.apply(rec)
+	return nil
+}
+
+// Add stores a dead letter, replacing one with the same job id
+func (s *FileStore) Add(letter DeadLetter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.append(record{ID: letter.Job.ID, Letter: &letter})
+}
+
+// List returns the dead letters in the order they were added
+func (s *FileStore) List() ([]DeadLetter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	letters := make([]DeadLetter, 0, len(s.order))
+	for _, id := range s.order {
+		letters = append(letters, s.letters[id])
+	}
+	return letters, nil
+}
+
+// Get returns the dead letter for a job id or ErrNotFound
+func (s *FileStore) Get(id string) (DeadLetter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	letter, ok := s.letters[id]
+	if !ok {
+		return DeadLetter{}, ErrNotFound
+	}
+	return letter, nil
+}
+
+// Remove deletes the dead letter for a job id
+func (s *FileStore) Remove(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.letters[id]; !ok {
+		return ErrNotFound
+	}
+	return s.append(record{ID: id, Removed: true})
+}
+
+// Close closes the file, the store can not be used afterwards
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
@@ -0,0 +1,94 @@
+package queue
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getTempStorePath(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "paul-dead-letters")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return filepath.Join(dir, "dead-letters.jsonl"), func() { os.RemoveAll(dir) }
+}
+
+func getMockDeadLetter(id string) DeadLetter {
+	return DeadLetter{
+		Job:      Job{ID: id, Key: "Spazzy757/paul", Event: "issue_comment", Payload: []byte(`{"action":"created"}`)},
+		Error:    "github api error: 502",
+		Attempts: 3,
+		FailedAt: time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	t.Run("Test Dead Letters Survive A Restart", func(t *testing.T) {
+		path, cleanup := getTempStorePath(t)
+		defer cleanup()
+		store, err := OpenFileStore(path)
+		assert.Nil(t, err)
+		assert.Nil(t, store.Add(getMockDeadLetter("1")))
+		assert.Nil(t, store.Add(getMockDeadLetter("2")))
+		assert.Nil(t, store.Add(getMockDeadLetter("3")))
+		assert.Nil(t, store.Remove("2"))
+		assert.Nil(t, store.Close())
+
+		store, err = OpenFileStore(path)
+		assert.Nil(t, err)
+		defer store.Close()
+		letters, err := store.List()
+		assert.Nil(t, err)
+		assert.Equal(t, []DeadLetter{getMockDeadLetter("1"), getMockDeadLetter("3")}, letters)
+		_, err = store.Get("2")
+		assert.Equal(t, ErrNotFound, err)
+	})
+	t.Run("Test Adding The Same Job Replaces It", func(t *testing.T) {
+		path, cleanup := getTempStorePath(t)
+		defer cleanup()
+		store, err := OpenFileStore(path)
+		assert.Nil(t, err)
+		defer store.Close()
+		letter := getMockDeadLetter("1")
+		assert.Nil(t, store.Add(letter))
+		letter.Attempts = 6
+		assert.Nil(t, store.Add(letter))
+		letters, err := store.List()
+		assert.Nil(t, err)
+		assert.Equal(t, []DeadLetter{letter}, letters)
+	})
+	t.Run("Test Torn Lines Are Skipped", func(t *testing.T) {
+		path, cleanup := getTempStorePath(t)
+		defer cleanup()
+		store, err := OpenFileStore(path)
+		assert.Nil(t, err)
+		assert.Nil(t, store.Add(getMockDeadLetter("1")))
+		assert.Nil(t, store.Close())
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+		assert.Nil(t, err)
+		file.WriteString(`{"id":"2","letter":{"job"`)
+		file.Close()
+
+		store, err = OpenFileStore(path)
+		assert.Nil(t, err)
+		defer store.Close()
+		letter, err := store.Get("1")
+		assert.Nil(t, err)
+		assert.Equal(t, getMockDeadLetter("1"), letter)
+		_, err = store.Get("2")
+		assert.Equal(t, ErrNotFound, err)
+	})
+	t.Run("Test Removing A Missing Dead Letter", func(t *testing.T) {
+		path, cleanup := getTempStorePath(t)
+		defer cleanup()
+		store, err := OpenFileStore(path)
+		assert.Nil(t, err)
+		defer store.Close()
+		assert.Equal(t, ErrNotFound, store.Remove("1"))
+	})
+}
@@ -0,0 +1,242 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrQueueFull is returned when a job can not be queued without blocking
+	ErrQueueFull = errors.New("queue is full")
+	// ErrClosed is returned when a job is queued after Shutdown was called
+	ErrClosed = errors.New("queue is shut down")
+)
+
+// Job is a webhook delivery waiting to be processed
+type Job struct {
+	// ID is the delivery id GitHub sent with the webhook
+	ID string `json:"id"`
+	// Key orders jobs, jobs with the same key are processed one at a time
+	// in the order they were queued
+	Key string `json:"key"`
+	// Event is the type of webhook event
+	Event string `json:"event"`
+	// Payload is the validated body of the webhook
+	Payload []byte `json:"payload"`
+	// Attempt is how many times the job already failed, the Pool counts
+	// it up every time the job is retried
+	Attempt int `json:"attempt,omitempty"`
+}
+
+// RetryError is returned by a Handler to have the Pool run the job again
+// once Delay has passed
+type RetryError struct {
+	Delay time.Duration
+	Err   error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("retrying in %v: %v", e.Delay, e.Err)
+}
+
+// Unwrap returns the error the job failed with
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// Handler processes a job
+type Handler func(job Job) error
+
+/*
+Pool processes jobs with a fixed number of workers. Every key is always
+handled by the same worker so jobs for a key keep their order, while
+jobs for different keys run in parallel. Jobs that return a RetryError
+wait for their retry without holding up the worker, later jobs for the
+same key are kept back until the retry is done so they keep their order
+*/
+type Pool struct {
+	mu      sync.RWMutex
+	closed  bool
+	queues  []chan Job
+	handler Handler
+	wg      sync.WaitGroup
+}
+
+// NewPool starts workers that share a queue of queueSize jobs
+func NewPool(workers, queueSize int, handler Handler) *Pool {
+	if workers < 1 {
+		workers = 1
+	}
+	perWorker := (queueSize + workers - 1) / workers
+	if perWorker < 1 {
+		perWorker = 1
+	}
+	p := &Pool{handler: handler}
+	for i := 0; i < workers; i++ {
+		queue := make(chan Job, perWorker)
+		p.queues = append(p.queues, queue)
+		p.wg.Add(1)
+		go p.work(queue)
+	}
+	return p
+}
+
+/*
+Enqueue queues a job without blocking, ErrQueueFull is returned when the
+worker for the key is too far behind so callers can apply backpressure
+*/
+func (p *Pool) Enqueue(job Job) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return ErrClosed
+	}
+	select {
+	case p.queues[p.worker(job.Key)] <- job:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+/*
+Shutdown stops accepting jobs and waits for queued and in-flight jobs to
+finish, it returns the context error if they do not finish in time
+*/
+func (p *Pool) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		for _, queue := range p.queues {
+			close(queue)
+		}
+	}
+	p.mu.Unlock()
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// worker picks the worker that handles a key
+func (p *Pool) worker(key string) int {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return int(h.Sum32() % uint32(len(p.queues)))
+}
+
+// retry is a job waiting to be run again, jobs for the same key that
+// were queued meanwhile wait behind it
+type retry struct {
+	job    Job
+	due    time.Time
+	parked []Job
+}
+
+func (p *Pool) work(queue chan Job) {
+	defer p.wg.Done()
+	retries := map[string]*retry{}
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
+	for queue != nil || len(retries) > 0 {
+		var due <-chan time.Time
+		if next, ok := nextRetry(retries); ok {
+			timer.Reset(time.Until(next))
+			due = timer.C
+		}
+		select {
+		case job, ok := <-queue:
+			if !ok {
+				// shutting down, jobs waiting to be retried run straight away
+				queue = nil
+				for _, r := range retries {
+					r.due = time.Time{}
+				}
+			} else if r, waiting := retries[job.Key]; waiting {
+				r.parked = append(r.parked, job)
+			} else {
+				p.process(job, retries)
+			}
+		case <-due:
+			now := time.Now()
+			for key, r := range retries {
+				if !now.Before(r.due) {
+					delete(retries, key)
+					p.resume(r, retries)
+				}
+			}
+		}
+		if due != nil && !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+	}
+}
+
+// nextRetry returns when the next job waiting to be retried is due
+func nextRetry(retries map[string]*retry) (time.Time, bool) {
+	var next time.Time
+	for _, r := range retries {
+		if next.IsZero() || r.due.Before(next) {
+			next = r.due
+		}
+	}
+	return next, len(retries) > 0
+}
+
+// process runs a job, a job that has to be retried holds up its key
+func (p *Pool) process(job Job, retries map[string]*retry) {
+	if delay, ok := p.run(job); ok {
+		job.Attempt++
+		retries[job.Key] = &retry{job: job, due: time.Now().Add(delay)}
+	}
+}
+
+// resume retries a job and then runs the jobs that waited behind it,
+// unless one of them has to be retried as well
+func (p *Pool) resume(r *retry, retries map[string]*retry) {
+	p.process(r.job, retries)
+	for i, job := range r.parked {
+		if waiting, ok := retries[job.Key]; ok {
+			waiting.parked = append(waiting.parked, r.parked[i:]...)
+			return
+		}
+		p.process(job, retries)
+	}
+}
+
+/*
+run handles a single job, a panicking handler does not stop the worker.
+It returns the delay when the handler asked for the job to be retried
+*/
+func (p *Pool) run(job Job) (delay time.Duration, retry bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Job %v for %v panicked: %v", job.ID, job.Key, r)
+		}
+	}()
+	err := p.handler(job)
+	var retryErr *RetryError
+	if errors.As(err, &retryErr) {
+		log.Printf("Job %v for %v failed, retrying in %v: %v", job.ID, job.Key, retryErr.Delay, retryErr.Err)
+		return retryErr.Delay, true
+	}
+	if err != nil {
+		log.Printf("Job %v for %v failed: %v", job.ID, job.Key, err)
+	}
+	return 0, false
+}
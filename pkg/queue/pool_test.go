@@ -0,0 +1,124 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPool(t *testing.T) {
+	t.Run("Test Jobs With The Same Key Keep Their Order", func(t *testing.T) {
+		var mu sync.Mutex
+		processed := map[string][]string{}
+		p := NewPool(4, 100, func(job Job) error {
+			mu.Lock()
+			defer mu.Unlock()
+			processed[job.Key] = append(processed[job.Key], job.ID)
+			return nil
+		})
+		expected := map[string][]string{}
+		for i := 0; i < 20; i++ {
+			for _, key := range []string{"a/one", "b/two", "c/three"} {
+				id := fmt.Sprintf("%v-%d", key, i)
+				expected[key] = append(expected[key], id)
+				assert.Nil(t, p.Enqueue(Job{ID: id, Key: key}))
+			}
+		}
+		assert.Nil(t, p.Shutdown(context.Background()))
+		assert.Equal(t, expected, processed)
+	})
+	t.Run("Test Full Queue Applies Backpressure", func(t *testing.T) {
+		started := make(chan struct{}, 2)
+		release := make(chan struct{})
+		p := NewPool(1, 1, func(job Job) error {
+			started <- struct{}{}
+			<-release
+			return nil
+		})
+		assert.Nil(t, p.Enqueue(Job{ID: "1"}))
+		// Wait for the worker to pick up the first job
+		<-started
+		assert.Nil(t, p.Enqueue(Job{ID: "2"}))
+		assert.Equal(t, ErrQueueFull, p.Enqueue(Job{ID: "3"}))
+		close(release)
+		assert.Nil(t, p.Shutdown(context.Background()))
+	})
+	t.Run("Test Shutdown Drains Queued Jobs", func(t *testing.T) {
+		var mu sync.Mutex
+		count := 0
+		p := NewPool(2, 10, func(job Job) error {
+			time.Sleep(time.Millisecond)
+			mu.Lock()
+			defer mu.Unlock()
+			count++
+			return nil
+		})
+		for i := 0; i < 10; i++ {
+			assert.Nil(t, p.Enqueue(Job{ID: fmt.Sprint(i), Key: fmt.Sprint(i)}))
+		}
+		assert.Nil(t, p.Shutdown(context.Background()))
+		assert.Equal(t, 10, count)
+		assert.Equal(t, ErrClosed, p.Enqueue(Job{ID: "late"}))
+	})
+	t.Run("Test Shutdown Gives Up When The Context Ends", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+		p := NewPool(1, 1, func(job Job) error {
+			<-release
+			return nil
+		})
+		assert.Nil(t, p.Enqueue(Job{ID: "1"}))
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.Equal(t, context.DeadlineExceeded, p.Shutdown(ctx))
+	})
+	t.Run("Test Panicking Jobs Do Not Stop The Worker", func(t *testing.T) {
+		done := false
+		p := NewPool(1, 2, func(job Job) error {
+			if job.ID == "panic" {
+				panic("boom")
+			}
+			done = true
+			return nil
+		})
+		assert.Nil(t, p.Enqueue(Job{ID: "panic"}))
+		assert.Nil(t, p.Enqueue(Job{ID: "ok"}))
+		assert.Nil(t, p.Shutdown(context.Background()))
+		assert.True(t, done)
+	})
+	t.Run("Test Retries Do Not Hold Up Other Keys", func(t *testing.T) {
+		var processed []string
+		p := NewPool(1, 10, func(job Job) error {
+			if job.ID == "a-1" && job.Attempt == 0 {
+				return &RetryError{Delay: time.Hour, Err: fmt.Errorf("502")}
+			}
+			processed = append(processed, job.ID)
+			return nil
+		})
+		for _, job := range []Job{{ID: "a-1", Key: "a"}, {ID: "a-2", Key: "a"}, {ID: "b-1", Key: "b"}} {
+			assert.Nil(t, p.Enqueue(job))
+		}
+		// jobs waiting to be retried run straight away once shutting down
+		assert.Nil(t, p.Shutdown(context.Background()))
+		assert.Equal(t, []string{"b-1", "a-1", "a-2"}, processed)
+	})
+	t.Run("Test Jobs Wait Behind A Job That Is Retried Again", func(t *testing.T) {
+		var processed []string
+		p := NewPool(1, 10, func(job Job) error {
+			if job.ID == "a-1" && job.Attempt < 2 {
+				return &RetryError{Delay: time.Millisecond, Err: fmt.Errorf("502")}
+			}
+			processed = append(processed, job.ID)
+			return nil
+		})
+		assert.Nil(t, p.Enqueue(Job{ID: "a-1", Key: "a"}))
+		assert.Nil(t, p.Enqueue(Job{ID: "a-2", Key: "a"}))
+		time.Sleep(20 * time.Millisecond)
+		assert.Nil(t, p.Shutdown(context.Background()))
+		assert.Equal(t, []string{"a-1", "a-2"}, processed)
+	})
+}
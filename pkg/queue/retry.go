@@ -0,0 +1,135 @@
+package queue
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RetryPolicy controls how failed jobs are retried
+type RetryPolicy struct {
+	// Attempts is how many times a job is run before it is dead-lettered
+	Attempts int
+	// Backoff is the wait before the first retry, it doubles every retry
+	Backoff time.Duration
+	// MaxBackoff caps the wait between retries
+	MaxBackoff time.Duration
+	// Retryable reports whether a failure is worth retrying, failures
+	// that are not retryable are dropped instead of dead-lettered
+	Retryable func(err error) bool
+}
+
+// delay returns the wait before the given retry, starting from 1
+func (p RetryPolicy) delay(retry int) time.Duration {
+	delay := p.Backoff
+	for i := 1; i < retry; i++ {
+		delay *= 2
+		if p.MaxBackoff > 0 && delay >= p.MaxBackoff {
+			return p.MaxBackoff
+		}
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		return p.MaxBackoff
+	}
+	return delay
+}
+
+func (p RetryPolicy) retryable(err error) bool {
+	return p.Retryable == nil || p.Retryable(err)
+}
+
+/*
+Retrier runs a handler with retries and exponential backoff, jobs that
+still fail after every attempt are added to a DeadLetterStore
+*/
+type Retrier struct {
+	handler Handler
+	policy  RetryPolicy
+	store   DeadLetterStore
+	stop    chan struct{}
+	once    sync.Once
+}
+
+// NewRetrier wraps handler with the retry policy
+func NewRetrier(handler Handler, policy RetryPolicy, store DeadLetterStore) *Retrier {
+	if policy.Attempts < 1 {
+		policy.Attempts = 1
+	}
+	return &Retrier{
+		handler: handler,
+		policy:  policy,
+		store:   store,
+		stop:    make(chan struct{}),
+	}
+}
+
+/*
+Handle runs the job once, it is used as the Handler of a Pool. Failed
+jobs are handed back to the Pool with a RetryError so the worker runs
+jobs for other keys during the backoff. Jobs that fail with an error that
+is not retryable are dropped and jobs that run out of attempts, or fail
+after Stop was called, are dead-lettered
+*/
+func (r *Retrier) Handle(job Job) error {
+	err := r.call(job)
+	if err == nil {
+		return nil
+	}
+	if !r.policy.retryable(err) {
+		return err
+	}
+	attempts := job.Attempt + 1
+	if attempts >= r.policy.Attempts || r.stopped() {
+		return r.deadLetter(job, attempts, err)
+	}
+	return &RetryError{Delay: r.policy.delay(attempts), Err: err}
+}
+
+/*
+Stop dead-letters jobs that fail from now on instead of retrying them, it
+is called before draining the pool, which runs jobs waiting to be retried
+straight away so they do not hold up the shutdown
+*/
+func (r *Retrier) Stop() {
+	r.once.Do(func() {
+		close(r.stop)
+	})
+}
+
+func (r *Retrier) stopped() bool {
+	select {
+	case <-r.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// call runs the handler turning a panic into an error so it is retried
+func (r *Retrier) call(job Job) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return r.handler(job)
+}
+
+func (r *Retrier) deadLetter(job Job, attempts int, err error) error {
+	if job.ID == "" {
+		job.ID = strconv.FormatInt(time.Now().UnixNano(), 10)
+	}
+	// replayed dead letters start over with every attempt
+	job.Attempt = 0
+	letter := DeadLetter{
+		Job:      job,
+		Error:    err.Error(),
+		Attempts: attempts,
+		FailedAt: time.Now().UTC(),
+	}
+	if storeErr := r.store.Add(letter); storeErr != nil {
+		return fmt.Errorf("unable to dead-letter job after %v attempts (%v): %w", attempts, err, storeErr)
+	}
+	return fmt.Errorf("dead-lettered after %v attempts: %w", attempts, err)
+}
@@ -0,0 +1,148 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockStore struct {
+	mu      sync.Mutex
+	letters []DeadLetter
+}
+
+func (m *mockStore) Add(letter DeadLetter) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.letters = append(m.letters, letter)
+	return nil
+}
+
+func (m *mockStore) List() ([]DeadLetter, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.letters, nil
+}
+
+func (m *mockStore) Get(id string) (DeadLetter, error) {
+	return DeadLetter{}, ErrNotFound
+}
+
+func (m *mockStore) Remove(id string) error {
+	return ErrNotFound
+}
+
+var errPermanent = errors.New("permanent")
+
+func getMockPolicy() RetryPolicy {
+	return RetryPolicy{
+		Attempts: 3,
+		Backoff:  time.Millisecond,
+		Retryable: func(err error) bool {
+			return !errors.Is(err, errPermanent)
+		},
+	}
+}
+
+func TestRetrier(t *testing.T) {
+	t.Run("Test Failed Jobs Are Retried", func(t *testing.T) {
+		calls := 0
+		done := make(chan struct{})
+		store := &mockStore{}
+		r := NewRetrier(func(job Job) error {
+			calls++
+			if calls < 3 {
+				return errors.New("502")
+			}
+			close(done)
+			return nil
+		}, getMockPolicy(), store)
+		p := NewPool(1, 1, r.Handle)
+		assert.Nil(t, p.Enqueue(Job{ID: "1"}))
+		<-done
+		assert.Nil(t, p.Shutdown(context.Background()))
+		assert.Equal(t, 3, calls)
+		assert.Equal(t, 0, len(store.letters))
+	})
+	t.Run("Test Failed Jobs Are Handed Back With The Backoff", func(t *testing.T) {
+		r := NewRetrier(func(job Job) error {
+			return errors.New("502")
+		}, getMockPolicy(), &mockStore{})
+		var retryErr *RetryError
+		assert.True(t, errors.As(r.Handle(Job{ID: "1", Attempt: 1}), &retryErr))
+		assert.Equal(t, 2*time.Millisecond, retryErr.Delay)
+		assert.EqualError(t, retryErr.Err, "502")
+	})
+	t.Run("Test Jobs Are Dead-Lettered After The Last Attempt", func(t *testing.T) {
+		store := &mockStore{}
+		r := NewRetrier(func(job Job) error {
+			return errors.New("502")
+		}, getMockPolicy(), store)
+		err := r.Handle(Job{ID: "1", Key: "Spazzy757/paul", Attempt: 2})
+		assert.EqualError(t, err, "dead-lettered after 3 attempts: 502")
+		assert.Equal(t, 1, len(store.letters))
+		assert.Equal(t, "1", store.letters[0].Job.ID)
+		assert.Equal(t, 0, store.letters[0].Job.Attempt)
+		assert.Equal(t, "502", store.letters[0].Error)
+		assert.Equal(t, 3, store.letters[0].Attempts)
+	})
+	t.Run("Test Errors That Are Not Retryable Are Dropped", func(t *testing.T) {
+		calls := 0
+		store := &mockStore{}
+		r := NewRetrier(func(job Job) error {
+			calls++
+			return errPermanent
+		}, getMockPolicy(), store)
+		assert.Equal(t, errPermanent, r.Handle(Job{ID: "1"}))
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, 0, len(store.letters))
+	})
+	t.Run("Test Panics Are Retried", func(t *testing.T) {
+		r := NewRetrier(func(job Job) error {
+			panic("nil map")
+		}, getMockPolicy(), &mockStore{})
+		var retryErr *RetryError
+		assert.True(t, errors.As(r.Handle(Job{ID: "1"}), &retryErr))
+		assert.EqualError(t, retryErr.Err, "panic: nil map")
+	})
+	t.Run("Test Stop Dead-Letters Jobs Waiting To Retry", func(t *testing.T) {
+		store := &mockStore{}
+		policy := getMockPolicy()
+		policy.Backoff = time.Hour
+		calls := 0
+		waiting := make(chan struct{})
+		r := NewRetrier(func(job Job) error {
+			if job.ID == "next" {
+				// the worker only gets here once the first job waits to be retried
+				close(waiting)
+				return nil
+			}
+			calls++
+			return errors.New("502")
+		}, policy, store)
+		p := NewPool(1, 2, r.Handle)
+		assert.Nil(t, p.Enqueue(Job{ID: "1", Key: "a"}))
+		assert.Nil(t, p.Enqueue(Job{ID: "next", Key: "b"}))
+		<-waiting
+		r.Stop()
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		assert.Nil(t, p.Shutdown(ctx))
+		assert.Equal(t, 2, calls)
+		assert.Equal(t, 1, len(store.letters))
+		assert.Equal(t, 2, store.letters[0].Attempts)
+	})
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := RetryPolicy{Backoff: time.Second, MaxBackoff: 5 * time.Second}
+	assert.Equal(t, time.Second, policy.delay(1))
+	assert.Equal(t, 2*time.Second, policy.delay(2))
+	assert.Equal(t, 4*time.Second, policy.delay(3))
+	assert.Equal(t, 5*time.Second, policy.delay(4))
+	assert.Equal(t, 5*time.Second, policy.delay(10))
+}
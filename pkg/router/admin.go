@@ -0,0 +1,101 @@
+package router
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Spazzy757/paul/pkg/github"
+	"github.com/Spazzy757/paul/pkg/queue"
+	"github.com/gorilla/mux"
+)
+
+// deadLetterSummary is a dead letter without its payload
+type deadLetterSummary struct {
+	ID       string    `json:"id"`
+	Key      string    `json:"key"`
+	Event    string    `json:"event"`
+	Error    string    `json:"error"`
+	Attempts int       `json:"attempts"`
+	FailedAt time.Time `json:"failed_at"`
+}
+
+/*
+RequireAdmin only lets requests with the admin token as a bearer token
+through to next
+*/
+func RequireAdmin(token string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		given := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if token == "" || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// ListDeadLettersHandler lists the dead letters without their payloads
+func ListDeadLettersHandler(store queue.DeadLetterStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		letters, err := store.List()
+		if err != nil {
+			log.Printf("Unable to list dead letters: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		summaries := make([]deadLetterSummary, 0, len(letters))
+		for _, letter := range letters {
+			summaries = append(summaries, deadLetterSummary{
+				ID:       letter.Job.ID,
+				Key:      letter.Job.Key,
+				Event:    letter.Job.Event,
+				Error:    letter.Error,
+				Attempts: letter.Attempts,
+				FailedAt: letter.FailedAt,
+			})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(summaries)
+	}
+}
+
+/*
+ReplayDeadLetterHandler removes a dead letter from the store and queues it
+to be processed again, if it fails again it is dead-lettered again. It is
+removed first so a replay that fails quickly is not removed with it
+*/
+func ReplayDeadLetterHandler(store queue.DeadLetterStore, q github.Queue) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := mux.Vars(r)["id"]
+		letter, err := store.Get(id)
+		if errors.Is(err, queue.ErrNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			log.Printf("Unable to get dead letter %v: %v", id, err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if err := store.Remove(id); err != nil {
+			log.Printf("Unable to remove dead letter %v: %v", id, err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if err := q.Enqueue(letter.Job); err != nil {
+			log.Printf("Unable to replay dead letter %v: %v", id, err)
+			if err := store.Add(letter); err != nil {
+				log.Printf("Dead letter %v could not be put back and was lost: %v", id, err)
+			}
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		log.Printf("Replaying dead letter %v", id)
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
@@ -0,0 +1,136 @@
+package router
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Spazzy757/paul/pkg/queue"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockQueue struct {
+	jobs []queue.Job
+	err  error
+	// process is called with every queued job, like a worker would
+	process func(job queue.Job)
+}
+
+func (m *mockQueue) Enqueue(job queue.Job) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.jobs = append(m.jobs, job)
+	if m.process != nil {
+		m.process(job)
+	}
+	return nil
+}
+
+func getMockStore(t *testing.T) (*queue.FileStore, func()) {
+	dir, err := ioutil.TempDir("", "paul-admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := queue.OpenFileStore(filepath.Join(dir, "dead-letters.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Add(queue.DeadLetter{
+		Job:      queue.Job{ID: "delivery-1", Key: "Spazzy757/paul", Event: "issue_comment", Payload: []byte(`{}`)},
+		Error:    "github api error: 502",
+		Attempts: 5,
+		FailedAt: time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC),
+	})
+	return store, func() {
+		store.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func doAdminRequest(q *mockQueue, store queue.DeadLetterStore, method, path, token string) *httptest.ResponseRecorder {
+	os.Setenv("ADMIN_TOKEN", "admin-token")
+	defer os.Unsetenv("ADMIN_TOKEN")
+	req := httptest.NewRequest(method, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	GetRouter(q, store).ServeHTTP(rec, req)
+	return rec
+}
+
+func TestListDeadLetters(t *testing.T) {
+	t.Run("Test Dead Letters Are Listed", func(t *testing.T) {
+		store, cleanup := getMockStore(t)
+		defer cleanup()
+		rec := doAdminRequest(&mockQueue{}, store, http.MethodGet, "/admin/dead-letters", "admin-token")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var summaries []deadLetterSummary
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &summaries))
+		assert.Equal(t, 1, len(summaries))
+		assert.Equal(t, "delivery-1", summaries[0].ID)
+		assert.Equal(t, 5, summaries[0].Attempts)
+	})
+	t.Run("Test Requests Without The Token Are Refused", func(t *testing.T) {
+		store, cleanup := getMockStore(t)
+		defer cleanup()
+		rec := doAdminRequest(&mockQueue{}, store, http.MethodGet, "/admin/dead-letters", "")
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		rec = doAdminRequest(&mockQueue{}, store, http.MethodGet, "/admin/dead-letters", "wrong")
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+	t.Run("Test Admin Routes Are Disabled Without A Token", func(t *testing.T) {
+		store, cleanup := getMockStore(t)
+		defer cleanup()
+		req := httptest.NewRequest(http.MethodGet, "/admin/dead-letters", nil)
+		rec := httptest.NewRecorder()
+		GetRouter(&mockQueue{}, store).ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestReplayDeadLetter(t *testing.T) {
+	t.Run("Test Dead Letters Are Queued And Removed", func(t *testing.T) {
+		store, cleanup := getMockStore(t)
+		defer cleanup()
+		q := &mockQueue{}
+		rec := doAdminRequest(q, store, http.MethodPost, "/admin/dead-letters/delivery-1/replay", "admin-token")
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		assert.Equal(t, 1, len(q.jobs))
+		assert.Equal(t, "delivery-1", q.jobs[0].ID)
+		_, err := store.Get("delivery-1")
+		assert.Equal(t, queue.ErrNotFound, err)
+	})
+	t.Run("Test Replays That Fail Quickly Are Kept", func(t *testing.T) {
+		store, cleanup := getMockStore(t)
+		defer cleanup()
+		q := &mockQueue{process: func(job queue.Job) {
+			store.Add(queue.DeadLetter{Job: job, Error: "github api error: 502", Attempts: 5})
+		}}
+		rec := doAdminRequest(q, store, http.MethodPost, "/admin/dead-letters/delivery-1/replay", "admin-token")
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		_, err := store.Get("delivery-1")
+		assert.Nil(t, err)
+	})
+	t.Run("Test Missing Dead Letters", func(t *testing.T) {
+		store, cleanup := getMockStore(t)
+		defer cleanup()
+		rec := doAdminRequest(&mockQueue{}, store, http.MethodPost, "/admin/dead-letters/missing/replay", "admin-token")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+	t.Run("Test Dead Letters Are Kept When The Queue Is Full", func(t *testing.T) {
+		store, cleanup := getMockStore(t)
+		defer cleanup()
+		q := &mockQueue{err: queue.ErrQueueFull}
+		rec := doAdminRequest(q, store, http.MethodPost, "/admin/dead-letters/delivery-1/replay", "admin-token")
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		_, err := store.Get("delivery-1")
+		assert.Nil(t, err)
+	})
+}
@@ -1,25 +1,72 @@
 package router
 
 import (
+	"expvar"
+	"log"
+	"net"
 	"net/http"
 
 	"github.com/Spazzy757/paul/pkg/github"
+	"github.com/Spazzy757/paul/pkg/helpers"
+	"github.com/Spazzy757/paul/pkg/queue"
 	"github.com/gorilla/mux"
 )
 
-//GetRouter .
-func GetRouter() *mux.Router {
+/*
+GetRouter returns the routes, webhooks are sent to q to be processed.
+The admin routes for dead letters and metrics are only added when
+ADMIN_TOKEN is set
+*/
+func GetRouter(q github.Queue, deadLetters queue.DeadLetterStore) *mux.Router {
 	r := mux.NewRouter()
-	r.HandleFunc("/", GithubWebHookHandler)
+	r.HandleFunc("/", GithubWebHookHandler(q))
+	token := helpers.GetEnv("ADMIN_TOKEN", "")
+	if token == "" {
+		log.Println("ADMIN_TOKEN is not set, admin routes are disabled")
+		return r
+	}
+	admin := r.PathPrefix("/admin").Subrouter()
+	admin.HandleFunc("/dead-letters", RequireAdmin(token, ListDeadLettersHandler(deadLetters))).Methods(http.MethodGet)
+	admin.HandleFunc("/dead-letters/{id}/replay", RequireAdmin(token, ReplayDeadLetterHandler(deadLetters, q))).Methods(http.MethodPost)
+	admin.HandleFunc("/metrics", RequireAdmin(token, expvar.Handler().ServeHTTP)).Methods(http.MethodGet)
 	return r
 }
 
-//GithubWebHookHandler .
-func GithubWebHookHandler(w http.ResponseWriter, r *http.Request) {
-	err := github.IncomingWebhook(r)
-	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		return
+/*
+GithubWebHookHandler validates and queues webhooks, responding with
+202 Accepted once they are queued as they are processed asynchronously
+*/
+func GithubWebHookHandler(q github.Queue) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := github.IncomingWebhook(r, q)
+		if err != nil {
+			if github.Classify(err) == github.ErrorKindValidation {
+				auditRejection(r, err)
+			} else {
+				log.Printf("An error occurred handling webhook %v: %v", r.Header.Get("X-GitHub-Delivery"), err)
+			}
+			w.WriteHeader(github.StatusCode(err))
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+/*
+auditRejection logs a rejected webhook with where it came from, forwarded
+for is logged as given as it is set by whoever sent the request
+*/
+func auditRejection(r *http.Request, err error) {
+	source, _, splitErr := net.SplitHostPort(r.RemoteAddr)
+	if splitErr != nil {
+		source = r.RemoteAddr
 	}
-	w.WriteHeader(http.StatusOK)
+	log.Printf(
+		"audit: rejected webhook delivery=%q event=%q source_ip=%v forwarded_for=%q: %v",
+		r.Header.Get("X-GitHub-Delivery"),
+		r.Header.Get("X-GitHub-Event"),
+		source,
+		r.Header.Get("X-Forwarded-For"),
+		err,
+	)
 }
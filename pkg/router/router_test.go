@@ -0,0 +1,34 @@
+package router
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGithubWebHookHandler(t *testing.T) {
+	t.Run("Test Rejected Webhooks Are Audited", func(t *testing.T) {
+		os.Setenv("SECRET_KEY", "secret")
+		defer os.Unsetenv("SECRET_KEY")
+		var logs bytes.Buffer
+		log.SetOutput(&logs)
+		defer log.SetOutput(os.Stderr)
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`))
+		req.RemoteAddr = "203.0.113.7:5555"
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-GitHub-Event", "issue_comment")
+		req.Header.Set("X-GitHub-Delivery", "delivery-1")
+		req.Header.Set("X-Hub-Signature", "sha1=0000")
+		rec := httptest.NewRecorder()
+		GithubWebHookHandler(&mockQueue{}).ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, logs.String(), `audit: rejected webhook delivery="delivery-1"`)
+		assert.Contains(t, logs.String(), "source_ip=203.0.113.7")
+	})
+}
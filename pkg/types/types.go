@@ -1,15 +1,24 @@
 package types
 
 import (
-	"log"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"unicode/utf8"
 
 	"gopkg.in/yaml.v2"
 )
 
 //PaulConfig defines the struct for type
 type PaulConfig struct {
-	Maintainers  []string     `yaml:"maintainers"`
-	PullRequests PullRequests `yaml:"pull_requests"`
+	Maintainers  []string        `yaml:"maintainers"`
+	PullRequests PullRequests    `yaml:"pull_requests"`
+	Commands     map[string]bool `yaml:"commands"`
+	Labels       Labels          `yaml:"labels"`
+	Merge        Merge           `yaml:"merge"`
+	Titles       Titles          `yaml:"titles"`
+	Reactions    Reactions       `yaml:"reactions"`
 }
 
 //PullRequests struct
@@ -17,12 +26,128 @@ type PullRequests struct {
 	OpenMessage string `yaml:"open_message"`
 	CatsEnabled bool   `yaml:"cats_enabled"`
 	DogsEnabled bool   `yaml:"dogs_enabled"`
+	LGTMLabel   string `yaml:"lgtm_label"`
+	HoldLabel   string `yaml:"hold_label"`
+}
+
+//HoldLabelName returns the label used by /hold, defaulting to do-not-merge/hold
+func (pr *PullRequests) HoldLabelName() string {
+	if pr.HoldLabel == "" {
+		return "do-not-merge/hold"
+	}
+	return pr.HoldLabel
+}
+
+//LGTMLabelName returns the label used by /lgtm, defaulting to lgtm
+func (pr *PullRequests) LGTMLabelName() string {
+	if pr.LGTMLabel == "" {
+		return "lgtm"
+	}
+	return pr.LGTMLabel
+}
+
+//Labels struct
+type Labels struct {
+	Allowed       []string          `yaml:"allowed"`
+	CreateMissing bool              `yaml:"create_missing"`
+	Colours       map[string]string `yaml:"colours"`
+}
+
+//IsAllowed checks the label against the allowed list which can contain globs
+func (l *Labels) IsAllowed(label string) bool {
+	for _, pattern := range l.Allowed {
+		if matched, err := path.Match(pattern, label); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+//Colour returns the configured colour of a label or GitHub's default grey
+func (l *Labels) Colour(label string) string {
+	if colour, ok := l.Colours[label]; ok {
+		return strings.TrimPrefix(colour, "#")
+	}
+	return "ededed"
+}
+
+//Merge struct
+type Merge struct {
+	Enabled         bool     `yaml:"enabled"`
+	Method          string   `yaml:"method"`
+	RequireApproval bool     `yaml:"require_approval"`
+	RequiredLabels  []string `yaml:"required_labels"`
+	BlockingLabels  []string `yaml:"blocking_labels"`
+	RequiredChecks  []string `yaml:"required_checks"`
+}
+
+//MergeMethod returns the configured merge method, defaulting to merge
+func (m *Merge) MergeMethod() string {
+	switch m.Method {
+	case "squash", "rebase":
+		return m.Method
+	}
+	return "merge"
+}
+
+//Titles struct
+type Titles struct {
+	Pattern   string `yaml:"pattern"`
+	MinLength int    `yaml:"min_length"`
+	MaxLength int    `yaml:"max_length"`
+}
+
+//Validate checks a title against the title policy
+func (t *Titles) Validate(title string) error {
+	if strings.TrimSpace(title) == "" {
+		return fmt.Errorf("the title can not be empty")
+	}
+	length := utf8.RuneCountInString(title)
+	if t.MinLength > 0 && length < t.MinLength {
+		return fmt.Errorf("the title must be at least %d characters long", t.MinLength)
+	}
+	if t.MaxLength > 0 && length > t.MaxLength {
+		return fmt.Errorf("the title must be at most %d characters long", t.MaxLength)
+	}
+	if t.Pattern != "" {
+		pattern, err := regexp.Compile(t.Pattern)
+		if err != nil {
+			return fmt.Errorf("the title pattern in PAUL.yaml is invalid: %v", err)
+		}
+		if !pattern.MatchString(title) {
+			return fmt.Errorf("the title must match the pattern `%v`", t.Pattern)
+		}
+	}
+	return nil
+}
+
+//Reactions struct
+type Reactions struct {
+	Enabled bool `yaml:"enabled"`
 }
 
 //LoadConfig loads the config for the type PaulConfig
-func (pc *PaulConfig) LoadConfig(config []byte) {
+func (pc *PaulConfig) LoadConfig(config []byte) error {
 	err := yaml.Unmarshal(config, pc)
 	if err != nil {
-		log.Fatalf("Unmarshal: %v", err)
+		return fmt.Errorf("unable to parse PAUL.yaml: %v", err)
+	}
+	return nil
+}
+
+/*
+CommandEnabled checks if the command with the given key is enabled,
+an explicit entry under commands wins over the older pull_requests flags
+*/
+func (pc *PaulConfig) CommandEnabled(key string) bool {
+	if enabled, ok := pc.Commands[key]; ok {
+		return enabled
+	}
+	switch key {
+	case "cat":
+		return pc.PullRequests.CatsEnabled
+	case "dog":
+		return pc.PullRequests.DogsEnabled
 	}
+	return false
 }
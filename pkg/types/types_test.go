@@ -26,3 +26,94 @@ func TestLoadConfig(t *testing.T) {
 	})
 
 }
+
+func TestCommandEnabled(t *testing.T) {
+	t.Run("Test Legacy Flags Enable Commands", func(t *testing.T) {
+		cfg := PaulConfig{PullRequests: PullRequests{CatsEnabled: true}}
+		assert.True(t, cfg.CommandEnabled("cat"))
+		assert.False(t, cfg.CommandEnabled("dog"))
+	})
+	t.Run("Test Commands Section Overrides Legacy Flags", func(t *testing.T) {
+		cfg := PaulConfig{
+			PullRequests: PullRequests{CatsEnabled: true},
+			Commands:     map[string]bool{"cat": false, "dog": true},
+		}
+		assert.False(t, cfg.CommandEnabled("cat"))
+		assert.True(t, cfg.CommandEnabled("dog"))
+	})
+	t.Run("Test Unknown Command Is Disabled", func(t *testing.T) {
+		cfg := PaulConfig{}
+		assert.False(t, cfg.CommandEnabled("unknown"))
+	})
+}
+
+func TestLabels(t *testing.T) {
+	labels := Labels{
+		Allowed: []string{"bug", "area/*"},
+		Colours: map[string]string{"bug": "#d73a4a"},
+	}
+	t.Run("Test Exact And Glob Labels Are Allowed", func(t *testing.T) {
+		assert.True(t, labels.IsAllowed("bug"))
+		assert.True(t, labels.IsAllowed("area/github"))
+		assert.False(t, labels.IsAllowed("area"))
+		assert.False(t, labels.IsAllowed("invalid"))
+	})
+	t.Run("Test Label Colours", func(t *testing.T) {
+		assert.Equal(t, "d73a4a", labels.Colour("bug"))
+		assert.Equal(t, "ededed", labels.Colour("area/github"))
+	})
+}
+
+func TestMergeMethod(t *testing.T) {
+	var tests = []struct {
+		method   string
+		expected string
+	}{
+		{"", "merge"},
+		{"squash", "squash"},
+		{"rebase", "rebase"},
+		{"fast-forward", "merge"},
+	}
+	for _, test := range tests {
+		t.Run("Test Merge Method "+test.method, func(t *testing.T) {
+			m := Merge{Method: test.method}
+			assert.Equal(t, test.expected, m.MergeMethod())
+		})
+	}
+}
+
+func TestTitlesValidate(t *testing.T) {
+	titles := Titles{
+		Pattern:   `^(feat|fix): `,
+		MinLength: 10,
+		MaxLength: 20,
+	}
+	var tests = []struct {
+		title string
+		valid bool
+	}{
+		{"fix: a bug", true},
+		{"fix: it", false},
+		{"feat: a very long title", false},
+		{"Fixed a bug", false},
+		{"   ", false},
+	}
+	for _, test := range tests {
+		t.Run("Test Title "+test.title, func(t *testing.T) {
+			err := titles.Validate(test.title)
+			assert.Equal(t, test.valid, err == nil)
+		})
+	}
+	t.Run("Test Invalid Pattern", func(t *testing.T) {
+		invalid := Titles{Pattern: "("}
+		assert.Contains(t, invalid.Validate("title").Error(), "pattern in PAUL.yaml is invalid")
+	})
+}
+
+func TestLoadInvalidConfig(t *testing.T) {
+	t.Run("Test Invalid YAML Returns An Error", func(t *testing.T) {
+		var paulConfig PaulConfig
+		err := paulConfig.LoadConfig([]byte("maintainers: [unclosed"))
+		assert.NotNil(t, err)
+	})
+}